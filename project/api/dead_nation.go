@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/clients"
+	"github.com/ThreeDotsLabs/go-event-driven/common/clients/dead_nation"
+)
+
+type DeadNationAPIClient struct {
+	// we are not mocking this client: it's pointless to use interface here
+	clients *clients.Clients
+}
+
+func NewDeadNationAPIClient(clients *clients.Clients) *DeadNationAPIClient {
+	if clients == nil {
+		panic("NewDeadNationAPIClient: clients is nil")
+	}
+
+	return &DeadNationAPIClient{clients: clients}
+}
+
+func (c DeadNationAPIClient) BookInDeadNation(ctx context.Context, request entities.DeadNationBooking) error {
+	resp, err := c.clients.DeadNation.PostTicketBookingWithResponse(
+		ctx,
+		dead_nation.PostTicketBookingRequest{
+			BookingId:       request.BookingID,
+			CustomerAddress: request.CustomerEmail,
+			EventId:         request.DeadNationEventID,
+			NumberOfTickets: request.NumberOfTickets,
+		},
+		func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("Idempotency-Key", request.IdempotencyKey)
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to book place in Dead Nation: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("unexpected status code for POST dead-nation-api/ticket/booking: %d", resp.StatusCode())
+	}
+
+	return nil
+}
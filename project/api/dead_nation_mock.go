@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"tickets/entities"
+)
+
+type DeadNationMock struct {
+	lock               sync.Mutex
+	DeadNationBookings []entities.DeadNationBooking
+}
+
+func (d *DeadNationMock) BookInDeadNation(ctx context.Context, request entities.DeadNationBooking) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.DeadNationBookings = append(d.DeadNationBookings, request)
+
+	return nil
+}
+
+func (d *DeadNationMock) Bookings() []entities.DeadNationBooking {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return append([]entities.DeadNationBooking(nil), d.DeadNationBookings...)
+}
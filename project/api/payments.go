@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/clients"
+	"github.com/ThreeDotsLabs/go-event-driven/common/clients/payments"
+)
+
+type PaymentsServiceClient struct {
+	// we are not mocking this client: it's pointless to use interface here
+	clients *clients.Clients
+}
+
+func NewPaymentsServiceClient(clients *clients.Clients) *PaymentsServiceClient {
+	if clients == nil {
+		panic("NewPaymentsServiceClient: clients is nil")
+	}
+
+	return &PaymentsServiceClient{clients: clients}
+}
+
+func (c PaymentsServiceClient) RefundPayment(ctx context.Context, refund entities.PaymentRefund) error {
+	resp, err := c.clients.Payments.PutRefundsWithResponse(ctx, payments.PaymentRefundRequest{
+		PaymentReference: refund.TicketID,
+		Reason:           refund.RefundReason,
+		DeduplicationId:  &refund.IdempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post refund for payment %s: %w", refund.TicketID, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("unexpected status code for PUT payments-api/refunds: %d", resp.StatusCode())
+	}
+
+	return nil
+}
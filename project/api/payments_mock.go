@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"tickets/entities"
+)
+
+type PaymentsMock struct {
+	lock    sync.Mutex
+	Refunds []entities.PaymentRefund
+}
+
+func (p *PaymentsMock) RefundPayment(ctx context.Context, refund entities.PaymentRefund) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.Refunds = append(p.Refunds, refund)
+
+	return nil
+}
+
+func (p *PaymentsMock) PaymentRefunds() []entities.PaymentRefund {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return append([]entities.PaymentRefund(nil), p.Refunds...)
+}
@@ -29,7 +29,7 @@ func (c ReceiptsServiceClient) IssueReceipt(ctx context.Context, request entitie
 	resp, err := c.clients.Receipts.PutReceiptsWithResponse(ctx, receipts.CreateReceipt{
 		IdempotencyKey: &idempotencyKey,
 		Price: receipts.Money{
-			MoneyAmount:   request.Price.Amount,
+			MoneyAmount:   request.Price.Amount.String(),
 			MoneyCurrency: request.Price.Currency,
 		},
 		TicketId: request.TicketID,
@@ -55,3 +55,20 @@ func (c ReceiptsServiceClient) IssueReceipt(ctx context.Context, request entitie
 		return entities.IssueReceiptResponse{}, fmt.Errorf("unexpected status code for POST receipts-api/receipts: %d", resp.StatusCode())
 	}
 }
+
+func (c ReceiptsServiceClient) VoidReceipt(ctx context.Context, request entities.VoidReceipt) error {
+	resp, err := c.clients.Receipts.PutVoidReceiptWithResponse(ctx, receipts.VoidReceiptRequest{
+		IdempotentId: &request.IdempotencyKey,
+		Reason:       request.Reason,
+		TicketId:     request.TicketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to void receipt: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("unexpected status code for PUT receipts-api/void-receipt: %d", resp.StatusCode())
+	}
+
+	return nil
+}
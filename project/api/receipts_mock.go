@@ -11,6 +11,7 @@ import (
 type ReceiptsServiceMock struct {
 	lock           sync.Mutex
 	IssuedReceipts []entities.IssueReceiptRequest
+	VoidedReceipts []entities.VoidReceipt
 }
 
 func (r *ReceiptsServiceMock) IssueReceipt(ctx context.Context, request entities.IssueReceiptRequest) (entities.IssueReceiptResponse, error) {
@@ -24,3 +25,26 @@ func (r *ReceiptsServiceMock) IssueReceipt(ctx context.Context, request entities
 		IssuedAt:      time.Now(),
 	}, nil
 }
+
+func (r *ReceiptsServiceMock) Receipts() []entities.IssueReceiptRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]entities.IssueReceiptRequest(nil), r.IssuedReceipts...)
+}
+
+func (r *ReceiptsServiceMock) VoidReceipt(ctx context.Context, request entities.VoidReceipt) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.VoidedReceipts = append(r.VoidedReceipts, request)
+
+	return nil
+}
+
+func (r *ReceiptsServiceMock) Voided() []entities.VoidReceipt {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]entities.VoidReceipt(nil), r.VoidedReceipts...)
+}
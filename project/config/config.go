@@ -0,0 +1,227 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tickets/message"
+	"tickets/message/event"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of the service.
+//
+// It's loaded from the defaults, an optional YAML file, environment variables and command-line flags, in this order,
+// so flags override environment variables, which override the file. See Load for the names of the settings.
+type Config struct {
+	GatewayAddr string          `yaml:"gateway_addr"`
+	Broker      BrokerConfig    `yaml:"broker"`
+	Storage     StorageConfig   `yaml:"storage"`
+	Redis       RedisConfig     `yaml:"redis"`
+	Postgres    PostgresConfig  `yaml:"postgres"`
+	HTTP        HTTPConfig      `yaml:"http"`
+	Health      HealthConfig    `yaml:"health"`
+	Messaging   MessagingConfig `yaml:"messaging"`
+	// Handler is the default policy of event and command handlers, some handlers override it.
+	Handler message.HandlerPolicy `yaml:"handler"`
+	// ShutdownTimeout limits how long in-flight HTTP requests and messages can take when the service stops.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type BrokerConfig struct {
+	Backend message.BrokerBackend `yaml:"backend"`
+	// PollInterval is how often subscribers of the Postgres broker poll their topics.
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+type StorageBackend string
+
+const (
+	StoragePostgres StorageBackend = "postgres"
+	// StorageMemory keeps all data in memory, it's lost when the service stops.
+	StorageMemory StorageBackend = "memory"
+)
+
+func ParseStorageBackend(backend string) (StorageBackend, error) {
+	switch b := StorageBackend(backend); b {
+	case StoragePostgres, StorageMemory:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unknown storage %q, supported storages: postgres, memory", backend)
+	}
+}
+
+type StorageConfig struct {
+	Backend StorageBackend `yaml:"backend"`
+}
+
+// UsesPostgres reports whether the storage or the broker needs a connection to Postgres.
+func (c Config) UsesPostgres() bool {
+	return c.Storage.Backend == StoragePostgres || c.Broker.Backend == message.BrokerPostgres
+}
+
+// RedisConfig is only used by the Redis broker.
+type RedisConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+// PostgresConfig is only used by the Postgres storage and the Postgres broker.
+type PostgresConfig struct {
+	URL Secret `yaml:"url"`
+}
+
+type HTTPConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout limits every readiness check.
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	// ProbeGateway adds the gateway to the readiness checks, it's optional, so the service stays ready when it's down.
+	ProbeGateway bool `yaml:"probe_gateway"`
+	// ShutdownDelay is how long the service reports it's not ready before the HTTP server stops,
+	// so load balancers stop sending requests to it first. It's a part of the shutdown timeout.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+}
+
+type MessagingConfig struct {
+	Namespace    message.Namespace `yaml:"namespace"`
+	EventsFormat event.Format      `yaml:"events_format"`
+	// ConsumerGroupPrefix is prepended to handler names to get their consumer groups.
+	ConsumerGroupPrefix string `yaml:"consumer_group_prefix"`
+	// ReplayConsumerGroup makes the replay-safe handlers also consume events replayed to this consumer group.
+	ReplayConsumerGroup   string          `yaml:"replay_consumer_group"`
+	OutboxPollInterval    time.Duration   `yaml:"outbox_poll_interval"`
+	SchedulerPollInterval time.Duration   `yaml:"scheduler_poll_interval"`
+	RetryDelays           []time.Duration `yaml:"retry_delays"`
+}
+
+func Default() Config {
+	return Config{
+		Broker: BrokerConfig{
+			Backend:      message.BrokerRedis,
+			PollInterval: time.Millisecond * 100,
+		},
+		Storage: StorageConfig{
+			Backend: StoragePostgres,
+		},
+		HTTP: HTTPConfig{
+			Addr: ":8080",
+		},
+		Health: HealthConfig{
+			CheckTimeout:  time.Second * 2,
+			ShutdownDelay: time.Second * 5,
+		},
+		Messaging: MessagingConfig{
+			EventsFormat:          event.FormatJSON,
+			ConsumerGroupPrefix:   "svc-tickets",
+			OutboxPollInterval:    time.Millisecond * 100,
+			SchedulerPollInterval: time.Second,
+			RetryDelays:           message.DefaultRetryDelays,
+		},
+		Handler:         message.DefaultHandlerPolicy(),
+		ShutdownTimeout: time.Second * 30,
+	}
+}
+
+// Validate returns all invalid settings at once, so they can be fixed in one go.
+func (c Config) Validate() error {
+	var errs []error
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"gateway address", c.GatewayAddr},
+		{"HTTP address", c.HTTP.Addr},
+	}
+	for _, setting := range required {
+		if setting.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", setting.name))
+		}
+	}
+
+	if _, err := message.ParseBrokerBackend(string(c.Broker.Backend)); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Broker.Backend == message.BrokerRedis && c.Redis.Addr == "" {
+		errs = append(errs, fmt.Errorf("redis address is required by the redis broker"))
+	}
+	if _, err := ParseStorageBackend(string(c.Storage.Backend)); err != nil {
+		errs = append(errs, err)
+	}
+	if c.UsesPostgres() && c.Postgres.URL.Value() == "" {
+		errs = append(errs, fmt.Errorf("postgres URL is required by the postgres storage and broker"))
+	}
+
+	// the Postgres broker stores payloads in JSON columns
+	if c.Broker.Backend == message.BrokerPostgres && c.Messaging.EventsFormat == event.FormatProtobuf {
+		errs = append(errs, fmt.Errorf("the postgres broker doesn't support protobuf events"))
+	}
+
+	if _, err := message.ParseNamespace(string(c.Messaging.Namespace)); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := event.ParseFormat(string(c.Messaging.EventsFormat)); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Messaging.ConsumerGroupPrefix == "" {
+		errs = append(errs, fmt.Errorf("consumer group prefix is required"))
+	}
+	if c.Broker.PollInterval <= 0 || c.Messaging.OutboxPollInterval <= 0 || c.Messaging.SchedulerPollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("poll intervals must be positive"))
+	}
+	for _, delay := range c.Messaging.RetryDelays {
+		if delay <= 0 {
+			errs = append(errs, fmt.Errorf("retry delays must be positive"))
+			break
+		}
+	}
+	if c.Health.CheckTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("health check timeout must be positive"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown timeout must be positive"))
+	}
+	if c.Health.ShutdownDelay < 0 || c.Health.ShutdownDelay >= c.ShutdownTimeout {
+		errs = append(errs, fmt.Errorf("health shutdown delay must not be negative and must be shorter than the shutdown timeout"))
+	}
+	if err := c.Handler.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid handler policy: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns the config as YAML, with secrets redacted, so it can be logged.
+func (c Config) Redacted() string {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("could not marshal config: %s", err)
+	}
+
+	return string(out)
+}
+
+// Secret is a setting which must not be logged, it's redacted when formatted or marshaled.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func (s Secret) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tickets/message"
+	"tickets/message/event"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load loads and validates the config, and returns the remaining command-line arguments (for example a subcommand).
+//
+// Every setting has a flag, and an environment variable with the name of the flag in upper case,
+// for example -redis-addr and REDIS_ADDR. The YAML file is set by -config-file or CONFIG_FILE.
+func Load(args []string) (Config, []string, error) {
+	return load(args, os.Getenv)
+}
+
+func load(args []string, getenv func(string) string) (Config, []string, error) {
+	config := Default()
+
+	flags := newFlagSet(&config)
+
+	// flags are applied last, so they are only recorded while parsing
+	var setFlags []recordedFlag
+	flags.VisitAll(func(f *flag.Flag) {
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		f.Value = &recordingValue{
+			name:     f.Name,
+			isBool:   ok && boolFlag.IsBoolFlag(),
+			recorded: &setFlags,
+		}
+	})
+	if err := flags.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+	remainingArgs := flags.Args()
+
+	configFile := getenv(envName("config-file"))
+	for _, f := range setFlags {
+		if f.name == "config-file" {
+			configFile = f.value
+		}
+	}
+	if configFile != "" {
+		if err := loadFile(configFile, &config); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	// the recording values were only needed for parsing
+	flags = newFlagSet(&config)
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		value := getenv(envName(f.Name))
+		if value == "" || err != nil {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid %s: %w", envName(f.Name), setErr)
+		}
+	})
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	for _, f := range setFlags {
+		if err := flags.Set(f.name, f.value); err != nil {
+			return Config{}, nil, fmt.Errorf("invalid -%s: %w", f.name, err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, remainingArgs, nil
+}
+
+func newFlagSet(c *Config) *flag.FlagSet {
+	flags := flag.NewFlagSet("tickets", flag.ContinueOnError)
+
+	var configFile string
+	flags.StringVar(&configFile, "config-file", "", "YAML file with the config")
+
+	flags.StringVar(&c.GatewayAddr, "gateway-addr", c.GatewayAddr, "address of the gateway with external APIs")
+	flags.Func("broker", "backend of the message broker: redis, postgres or gochannel", func(v string) error {
+		backend, err := message.ParseBrokerBackend(v)
+		c.Broker.Backend = backend
+		return err
+	})
+	flags.DurationVar(&c.Broker.PollInterval, "broker-poll-interval", c.Broker.PollInterval, "how often subscribers of the postgres broker poll")
+	flags.Func("storage", "storage of the service data: postgres or memory", func(v string) error {
+		backend, err := ParseStorageBackend(v)
+		c.Storage.Backend = backend
+		return err
+	})
+	flags.StringVar(&c.Redis.Addr, "redis-addr", c.Redis.Addr, "address of Redis, used by the redis broker")
+	flags.Func("postgres-url", "URL of Postgres, used by the postgres storage and broker", func(v string) error {
+		c.Postgres.URL = Secret(v)
+		return nil
+	})
+	flags.StringVar(&c.HTTP.Addr, "http-addr", c.HTTP.Addr, "address of the HTTP server")
+	flags.DurationVar(&c.Health.CheckTimeout, "health-check-timeout", c.Health.CheckTimeout, "timeout of every readiness check")
+	flags.BoolVar(&c.Health.ProbeGateway, "health-probe-gateway", c.Health.ProbeGateway, "check the gateway in the readiness probe")
+	flags.DurationVar(&c.Health.ShutdownDelay, "health-shutdown-delay", c.Health.ShutdownDelay, "how long the service is not ready before the HTTP server stops")
+
+	flags.Func("namespace", "namespace of topics, consumer groups and the outbox", func(v string) error {
+		namespace, err := message.ParseNamespace(v)
+		c.Messaging.Namespace = namespace
+		return err
+	})
+	flags.Func("events-format", "format of published events: json or protobuf", func(v string) error {
+		format, err := event.ParseFormat(v)
+		c.Messaging.EventsFormat = format
+		return err
+	})
+	flags.StringVar(&c.Messaging.ConsumerGroupPrefix, "consumer-group-prefix", c.Messaging.ConsumerGroupPrefix, "prefix of consumer groups")
+	flags.StringVar(&c.Messaging.ReplayConsumerGroup, "replay-consumer-group", c.Messaging.ReplayConsumerGroup, "consume events replayed to this consumer group")
+	flags.DurationVar(&c.Messaging.OutboxPollInterval, "outbox-poll-interval", c.Messaging.OutboxPollInterval, "how often the outbox is polled")
+	flags.DurationVar(&c.Messaging.SchedulerPollInterval, "scheduler-poll-interval", c.Messaging.SchedulerPollInterval, "how often scheduled messages are polled")
+	flags.Func("retry-delays", "comma separated delays of delayed retries", func(v string) error {
+		delays, err := parseDurations(v)
+		c.Messaging.RetryDelays = delays
+		return err
+	})
+
+	flags.IntVar(&c.Handler.MaxRetries, "handler-max-retries", c.Handler.MaxRetries, "in-process retries of handlers")
+	flags.Float64Var(&c.Handler.Multiplier, "handler-retry-multiplier", c.Handler.Multiplier, "multiplier of the interval between retries")
+	flags.DurationVar(&c.Handler.InitialInterval, "handler-retry-initial-interval", c.Handler.InitialInterval, "interval before the first retry")
+	flags.DurationVar(&c.Handler.MaxInterval, "handler-retry-max-interval", c.Handler.MaxInterval, "maximum interval between retries, 0 means no limit")
+	flags.DurationVar(&c.Handler.AttemptTimeout, "handler-attempt-timeout", c.Handler.AttemptTimeout, "timeout of a single attempt, 0 means no limit")
+	flags.DurationVar(&c.Handler.Deadline, "handler-deadline", c.Handler.Deadline, "timeout of all attempts together, 0 means no limit")
+
+	flags.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "how long in-flight requests and messages can take when stopping")
+
+	return flags
+}
+
+func loadFile(path string, c *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// envName returns the environment variable of the flag, for example REDIS_ADDR for -redis-addr.
+func envName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func parseDurations(v string) ([]time.Duration, error) {
+	var durations []time.Duration
+	for _, s := range strings.Split(v, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		durations = append(durations, d)
+	}
+
+	return durations, nil
+}
+
+type recordedFlag struct {
+	name  string
+	value string
+}
+
+type recordingValue struct {
+	name string
+	// isBool lets boolean flags be set without a value, like -health-probe-gateway
+	isBool   bool
+	recorded *[]recordedFlag
+}
+
+func (v *recordingValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func (v *recordingValue) String() string {
+	return ""
+}
+
+func (v *recordingValue) Set(value string) error {
+	*v.recorded = append(*v.recorded, recordedFlag{name: v.name, value: value})
+	return nil
+}
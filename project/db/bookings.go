@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"tickets/entities"
 	"tickets/message/event"
@@ -12,21 +13,32 @@ import (
 )
 
 type BookingRepository struct {
-	db    *pgxpool.Pool
-	stdDB *sql.DB
+	db             *pgxpool.Pool
+	stdDB          *sql.DB
+	eventMarshaler cqrs.CommandEventMarshaler
+	outboxTopic    string
 }
 
-func NewBookingRepository(db *pgxpool.Pool, stdDB *sql.DB) *BookingRepository {
+func NewBookingRepository(
+	db *pgxpool.Pool,
+	stdDB *sql.DB,
+	eventMarshaler cqrs.CommandEventMarshaler,
+	outboxTopic string,
+) *BookingRepository {
 	if db == nil {
 		panic("db passed to 'NewBookingRepository()' is nil!")
 	}
-	return &BookingRepository{db: db, stdDB: stdDB}
+	return &BookingRepository{db: db, stdDB: stdDB, eventMarshaler: eventMarshaler, outboxTopic: outboxTopic}
 }
 
-func (r *BookingRepository) Create(ctx context.Context, b entities.Booking) error {
+func (r *BookingRepository) Create(ctx context.Context, b entities.Booking) (err error) {
+	if b.NumberOfTickets <= 0 {
+		return entities.ErrInvalidTicketsBooking
+	}
+
 	q := `
 	INSERT INTO bookings (
-	  id, 
+	  id,
 	  show_id,
 	  number_of_tickets,
 	  customer_email
@@ -46,16 +58,45 @@ func (r *BookingRepository) Create(ctx context.Context, b entities.Booking) erro
 		err = tx.Commit()
 	}()
 
-	if _, err := tx.ExecContext(ctx, q, b.ID, b.ShowID, b.NumberOfTickets, b.CustomerEmail); err != nil {
+	// the show row is locked until the transaction ends, so concurrent bookings
+	// for the same show (even from other service instances) are serialized here
+	var availableTickets int
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT number_of_tickets FROM shows WHERE id = $1 FOR UPDATE`,
+		b.ShowID,
+	).Scan(&availableTickets)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrShowNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error: could not lock show: %w", err)
+	}
+
+	var bookedTickets int
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(number_of_tickets), 0) FROM bookings WHERE show_id = $1`,
+		b.ShowID,
+	).Scan(&bookedTickets)
+	if err != nil {
+		return fmt.Errorf("error: could not count booked tickets: %w", err)
+	}
+
+	if bookedTickets+b.NumberOfTickets > availableTickets {
+		return entities.ErrNotEnoughTicketsLeft
+	}
+
+	if _, err = tx.ExecContext(ctx, q, b.ID, b.ShowID, b.NumberOfTickets, b.CustomerEmail); err != nil {
 		return fmt.Errorf("error: failed to insert booking: %w", err)
 	}
 
-	outboxPublisher, err := outbox.NewPublisherForDb(ctx, tx)
+	outboxPublisher, err := outbox.NewPublisherForDb(ctx, tx, r.outboxTopic)
 	if err != nil {
 		return fmt.Errorf("could not create event bus: %w", err)
 	}
 
-	err = event.NewEventBus(outboxPublisher).Publish(ctx, entities.BookingMade{
+	err = event.NewEventBus(outboxPublisher, r.eventMarshaler).Publish(ctx, entities.BookingMade{
 		Header:          entities.NewEventHeader(),
 		BookingID:       b.ID,
 		NumberOfTickets: b.NumberOfTickets,
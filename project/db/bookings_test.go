@@ -0,0 +1,44 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"tickets/db/dbtest"
+	"tickets/message/event"
+	"tickets/message/outbox"
+
+	"github.com/ThreeDotsLabs/watermill"
+	_ "github.com/lib/pq"
+)
+
+var stdDB *sql.DB
+var getStdDbOnce sync.Once
+
+func getStdDb() *sql.DB {
+	getStdDbOnce.Do(func() {
+		var err error
+		stdDB, err = sql.Open("postgres", os.Getenv("POSTGRES_URL"))
+		if err != nil {
+			panic(err)
+		}
+
+		// bookings are published through the outbox, so its table has to exist
+		err = outbox.NewPostgresSubscriber(stdDB, time.Millisecond*100, watermill.NopLogger{}).SubscribeInitialize(outbox.Topic)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return stdDB
+}
+
+func TestBookingRepository(t *testing.T) {
+	dbtest.TestBookingRepository(
+		t,
+		NewBookingRepository(getDb(), getStdDb(), event.NewMarshaler(event.FormatJSON), outbox.Topic),
+		NewShowRepository(getDb()),
+	)
+}
@@ -0,0 +1,113 @@
+package dbtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type BookingRepository interface {
+	Create(ctx context.Context, booking entities.Booking) error
+}
+
+// TestBookingRepository checks the capacity of shows, so it needs the show repository the bookings are counted in.
+func TestBookingRepository(t *testing.T, bookings BookingRepository, shows ShowRepository) {
+	ctx := context.Background()
+
+	createShow := func(numberOfTickets int) entities.Show {
+		show := entities.Show{
+			ID:              uuid.New(),
+			DeadNationID:    uuid.New(),
+			NumberOfTickets: numberOfTickets,
+			StartTime:       now().Add(24 * time.Hour).Truncate(time.Second),
+			Title:           "Capacity test",
+			Venue:           "venue-" + uuid.NewString(),
+		}
+		require.NoError(t, shows.Create(ctx, show))
+
+		return show
+	}
+
+	newBooking := func(showID uuid.UUID, numberOfTickets int) entities.Booking {
+		return entities.Booking{
+			ID:              uuid.New(),
+			ShowID:          showID,
+			NumberOfTickets: numberOfTickets,
+			CustomerEmail:   "customer@example.com",
+		}
+	}
+
+	t.Run("concurrent bookings don't oversell the show", func(t *testing.T) {
+		show := createShow(5)
+
+		var wg sync.WaitGroup
+		var lock sync.Mutex
+		var booked, rejected int
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := bookings.Create(ctx, newBooking(show.ID, 1))
+
+				lock.Lock()
+				defer lock.Unlock()
+
+				if err == nil {
+					booked++
+					return
+				}
+				assert.ErrorIs(t, err, entities.ErrNotEnoughTicketsLeft)
+				rejected++
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, booked)
+		assert.Equal(t, 5, rejected)
+
+		availability, err := shows.ByID(ctx, show.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 5, availability.BookedTickets)
+		assert.Equal(t, 0, availability.RemainingTickets)
+	})
+
+	t.Run("booking more tickets than left", func(t *testing.T) {
+		show := createShow(3)
+
+		require.NoError(t, bookings.Create(ctx, newBooking(show.ID, 2)))
+		assert.ErrorIs(t, bookings.Create(ctx, newBooking(show.ID, 2)), entities.ErrNotEnoughTicketsLeft)
+		require.NoError(t, bookings.Create(ctx, newBooking(show.ID, 1)))
+
+		availability, err := shows.ByID(ctx, show.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, availability.BookedTickets)
+		assert.Equal(t, 0, availability.RemainingTickets)
+	})
+
+	t.Run("unknown show", func(t *testing.T) {
+		err := bookings.Create(ctx, newBooking(uuid.New(), 1))
+		assert.ErrorIs(t, err, entities.ErrShowNotFound)
+	})
+
+	t.Run("invalid number of tickets", func(t *testing.T) {
+		show := createShow(5)
+
+		for _, numberOfTickets := range []int{0, -1} {
+			err := bookings.Create(ctx, newBooking(show.ID, numberOfTickets))
+			assert.ErrorIs(t, err, entities.ErrInvalidTicketsBooking, "booking of %d tickets", numberOfTickets)
+		}
+
+		availability, err := shows.ByID(ctx, show.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, availability.BookedTickets)
+	})
+}
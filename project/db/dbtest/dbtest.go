@@ -0,0 +1,26 @@
+// Package dbtest has test cases shared by the Postgres repositories from the db package
+// and the in-memory repositories from db/memory, so both are checked for the same semantics.
+//
+// The Postgres database is shared by all tests, so the cases use random IDs and filter by them,
+// instead of expecting empty tables.
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// now returns the current time with the precision of Postgres timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func assertTime(t *testing.T, expected time.Time, actual *time.Time, msgAndArgs ...any) {
+	t.Helper()
+
+	require.NotNil(t, actual, msgAndArgs...)
+	assert.True(t, expected.Equal(*actual), "expected %s, got %s", expected, *actual)
+}
@@ -0,0 +1,134 @@
+package dbtest
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type EventRepository interface {
+	Append(ctx context.Context, event entities.StoredEvent) error
+	All(ctx context.Context, filter entities.StoredEventsFilter) ([]entities.StoredEvent, error)
+}
+
+func TestEventRepository(t *testing.T, repository EventRepository) {
+	ctx := context.Background()
+
+	newEvent := func(correlationID string, eventName string, publishedAt time.Time) entities.StoredEvent {
+		return entities.StoredEvent{
+			EventID:       uuid.NewString(),
+			EventName:     eventName,
+			Version:       1,
+			CorrelationID: correlationID,
+			PublishedAt:   publishedAt,
+			Payload:       json.RawMessage(`{}`),
+		}
+	}
+
+	t.Run("append is idempotent", func(t *testing.T) {
+		correlationID := uuid.NewString()
+		event := newEvent(correlationID, "TicketBookingConfirmed", now())
+		require.NoError(t, repository.Append(ctx, event))
+
+		duplicate := event
+		duplicate.EventName = "TicketRefunded"
+		require.NoError(t, repository.Append(ctx, duplicate))
+
+		events, err := repository.All(ctx, entities.StoredEventsFilter{CorrelationID: correlationID, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, event.EventName, events[0].EventName)
+		assertTime(t, event.PublishedAt, &events[0].PublishedAt)
+	})
+
+	t.Run("paging and filters", func(t *testing.T) {
+		correlationID := uuid.NewString()
+		publishedAt := now()
+
+		var events []entities.StoredEvent
+		for i := 0; i < 5; i++ {
+			events = append(events, newEvent(correlationID, "TicketBookingConfirmed", publishedAt.Add(time.Duration(i)*time.Second)))
+		}
+		// events published at the same time are ordered by ID
+		events = append(events, newEvent(correlationID, "TicketBookingConfirmed", events[2].PublishedAt))
+		refunded := newEvent(correlationID, "TicketRefunded", publishedAt)
+
+		for _, event := range events {
+			require.NoError(t, repository.Append(ctx, event))
+		}
+		require.NoError(t, repository.Append(ctx, refunded))
+		require.NoError(t, repository.Append(ctx, newEvent(uuid.NewString(), "TicketBookingConfirmed", publishedAt)))
+
+		sort.Slice(events, func(i, j int) bool {
+			if !events[i].PublishedAt.Equal(events[j].PublishedAt) {
+				return events[i].PublishedAt.Before(events[j].PublishedAt)
+			}
+			return events[i].EventID < events[j].EventID
+		})
+
+		filter := entities.StoredEventsFilter{
+			EventNames:    []string{"TicketBookingConfirmed"},
+			CorrelationID: correlationID,
+			Limit:         4,
+		}
+
+		firstPage, err := repository.All(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, eventIDs(events[:4]), eventIDs(firstPage))
+
+		filter.Offset = 4
+		secondPage, err := repository.All(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, eventIDs(events[4:]), eventIDs(secondPage))
+
+		filter.Offset = 0
+		var pagedByCursor []entities.StoredEvent
+		for {
+			page, err := repository.All(ctx, filter)
+			require.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+
+			pagedByCursor = append(pagedByCursor, page...)
+			cursor := page[len(page)-1].Cursor()
+			filter.After = &cursor
+		}
+		assert.Equal(t, eventIDs(events), eventIDs(pagedByCursor))
+
+		// both bounds are inclusive
+		from := events[1].PublishedAt
+		to := events[4].PublishedAt
+		between, err := repository.All(ctx, entities.StoredEventsFilter{
+			EventNames:    []string{"TicketBookingConfirmed"},
+			CorrelationID: correlationID,
+			From:          &from,
+			To:            &to,
+			Limit:         100,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, eventIDs(events[1:5]), eventIDs(between))
+
+		all, err := repository.All(ctx, entities.StoredEventsFilter{CorrelationID: correlationID, Limit: 100})
+		require.NoError(t, err)
+		assert.Contains(t, eventIDs(all), refunded.EventID)
+		assert.Len(t, all, len(events)+1)
+	})
+}
+
+func eventIDs(events []entities.StoredEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+
+	return ids
+}
@@ -0,0 +1,73 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type IdempotencyKeyRepository interface {
+	Start(ctx context.Context, key string, requestHash string) (*entities.IdempotentResponse, error)
+	Complete(ctx context.Context, key string, response entities.IdempotentResponse) error
+	Release(ctx context.Context, key string) error
+}
+
+func TestIdempotencyKeyRepository(t *testing.T, repository IdempotencyKeyRepository) {
+	ctx := context.Background()
+
+	t.Run("complete", func(t *testing.T) {
+		idempotencyKey := uuid.NewString()
+
+		response, err := repository.Start(ctx, idempotencyKey, "hash")
+		require.NoError(t, err)
+		assert.Nil(t, response)
+
+		_, err = repository.Start(ctx, idempotencyKey, "hash")
+		assert.ErrorIs(t, err, entities.ErrIdempotencyKeyInProgress)
+
+		_, err = repository.Start(ctx, idempotencyKey, "other-hash")
+		assert.ErrorIs(t, err, entities.ErrIdempotencyKeyReused)
+
+		expectedResponse := entities.IdempotentResponse{
+			StatusCode:  201,
+			ContentType: "application/json",
+			Body:        []byte(`{"booking_id":"1"}`),
+		}
+		require.NoError(t, repository.Complete(ctx, idempotencyKey, expectedResponse))
+
+		response, err = repository.Start(ctx, idempotencyKey, "hash")
+		require.NoError(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, expectedResponse, *response)
+
+		_, err = repository.Start(ctx, idempotencyKey, "other-hash")
+		assert.ErrorIs(t, err, entities.ErrIdempotencyKeyReused)
+
+		// completed keys are not released
+		require.NoError(t, repository.Release(ctx, idempotencyKey))
+		response, err = repository.Start(ctx, idempotencyKey, "hash")
+		require.NoError(t, err)
+		assert.NotNil(t, response)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		idempotencyKey := uuid.NewString()
+
+		_, err := repository.Start(ctx, idempotencyKey, "hash")
+		require.NoError(t, err)
+
+		require.NoError(t, repository.Release(ctx, idempotencyKey))
+
+		// after a release, the key can be used for a different request too
+		response, err := repository.Start(ctx, idempotencyKey, "other-hash")
+		require.NoError(t, err)
+		assert.Nil(t, response)
+
+		require.NoError(t, repository.Release(ctx, uuid.NewString()), "releasing an unknown key should be a no-op")
+	})
+}
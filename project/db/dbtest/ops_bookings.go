@@ -0,0 +1,171 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type OpsBookingsRepository interface {
+	OnBookingMade(ctx context.Context, event *entities.BookingMade) error
+	OnTicketBookingConfirmed(ctx context.Context, event *entities.TicketBookingConfirmed) error
+	OnTicketBookingCanceled(ctx context.Context, event *entities.TicketBookingCanceled) error
+	OnTicketRefunded(ctx context.Context, event *entities.TicketRefunded) error
+	OnTicketPrinted(ctx context.Context, event *entities.TicketPrinted) error
+	All(ctx context.Context, filter entities.OpsBookingsFilter) ([]entities.OpsBooking, error)
+	ByID(ctx context.Context, bookingID uuid.UUID) (entities.OpsBooking, error)
+}
+
+func TestOpsBookingsRepository(t *testing.T, repository OpsBookingsRepository) {
+	ctx := context.Background()
+
+	newBookingMade := func(showID uuid.UUID, bookedAt time.Time) *entities.BookingMade {
+		header := entities.NewEventHeader()
+		header.PublishedAt = bookedAt
+
+		return &entities.BookingMade{
+			Header:          header,
+			NumberOfTickets: 1,
+			BookingID:       uuid.New(),
+			CustomerEmail:   "customer@example.com",
+			ShowId:          showID,
+		}
+	}
+
+	t.Run("out of order events", func(t *testing.T) {
+		bookingID := uuid.New()
+		showID := uuid.New()
+		ticketID := uuid.NewString()
+
+		printed := &entities.TicketPrinted{
+			Header:   entities.NewEventHeader(),
+			TicketID: ticketID,
+			FileName: ticketID + "-ticket.html",
+		}
+		refunded := &entities.TicketRefunded{
+			Header:   entities.NewEventHeader(),
+			TicketID: ticketID,
+		}
+		confirmed := &entities.TicketBookingConfirmed{
+			Header:        entities.NewEventHeader(),
+			TicketID:      ticketID,
+			CustomerEmail: "customer@example.com",
+			Price:         entities.Price{Amount: entities.MustNewDecimal("30.00"), Currency: "EUR"},
+			BookingID:     bookingID.String(),
+		}
+		bookingMade := newBookingMade(showID, now())
+		bookingMade.BookingID = bookingID
+
+		// events are applied twice and in reverse order
+		for i := 0; i < 2; i++ {
+			require.NoError(t, repository.OnTicketPrinted(ctx, printed))
+			require.NoError(t, repository.OnTicketRefunded(ctx, refunded))
+			require.NoError(t, repository.OnTicketBookingConfirmed(ctx, confirmed))
+			require.NoError(t, repository.OnBookingMade(ctx, bookingMade))
+		}
+
+		booking, err := repository.ByID(ctx, bookingID)
+		require.NoError(t, err)
+
+		require.NotNil(t, booking.ShowID)
+		assert.Equal(t, showID, *booking.ShowID)
+		assert.Equal(t, 1, booking.NumberOfTickets)
+		assertTime(t, bookingMade.Header.PublishedAt, booking.BookedAt)
+
+		require.Len(t, booking.Tickets, 1)
+		ticket := booking.Tickets[0]
+		assert.Equal(t, ticketID, ticket.TicketID)
+		assert.Equal(t, entities.TicketStatusRefunded, ticket.Status)
+		assert.Equal(t, "30.00", ticket.Price.Amount.String())
+		assert.Equal(t, printed.FileName, ticket.PrintedFileName)
+		assert.NotNil(t, ticket.PrintedAt)
+
+		_, err = repository.ByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, entities.ErrOpsBookingNotFound)
+	})
+
+	t.Run("redelivered events keep the first timestamps", func(t *testing.T) {
+		bookingID := uuid.New()
+		ticketID := uuid.NewString()
+		confirmedAt := now()
+
+		confirmed := &entities.TicketBookingConfirmed{
+			Header:        entities.NewEventHeader(),
+			TicketID:      ticketID,
+			CustomerEmail: "customer@example.com",
+			Price:         entities.Price{Amount: entities.MustNewDecimal("30.00"), Currency: "EUR"},
+			BookingID:     bookingID.String(),
+		}
+		confirmed.Header.PublishedAt = confirmedAt
+		canceled := &entities.TicketBookingCanceled{
+			Header:        entities.NewEventHeader(),
+			TicketID:      ticketID,
+			CustomerEmail: confirmed.CustomerEmail,
+			Price:         confirmed.Price,
+		}
+		canceled.Header.PublishedAt = confirmedAt.Add(time.Minute)
+
+		require.NoError(t, repository.OnTicketBookingCanceled(ctx, canceled))
+		require.NoError(t, repository.OnTicketBookingConfirmed(ctx, confirmed))
+
+		republishedConfirmed := *confirmed
+		republishedConfirmed.Header.PublishedAt = confirmedAt.Add(time.Hour)
+		require.NoError(t, repository.OnTicketBookingConfirmed(ctx, &republishedConfirmed))
+		republishedCanceled := *canceled
+		republishedCanceled.Header.PublishedAt = confirmedAt.Add(time.Hour)
+		require.NoError(t, repository.OnTicketBookingCanceled(ctx, &republishedCanceled))
+
+		booking, err := repository.ByID(ctx, bookingID)
+		require.NoError(t, err)
+		assert.Nil(t, booking.ShowID, "booking details are unknown until BookingMade is handled")
+
+		require.Len(t, booking.Tickets, 1)
+		ticket := booking.Tickets[0]
+		assert.Equal(t, entities.TicketStatusCanceled, ticket.Status)
+		assertTime(t, confirmedAt, ticket.ConfirmedAt)
+		assertTime(t, canceled.Header.PublishedAt, ticket.CanceledAt)
+		assert.Nil(t, ticket.RefundedAt)
+	})
+
+	t.Run("filters and ordering", func(t *testing.T) {
+		showID := uuid.New()
+		day := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+		morning := newBookingMade(showID, day)
+		noon := newBookingMade(showID, day.Add(2*time.Hour))
+		nextDay := newBookingMade(showID, day.Add(24*time.Hour))
+		otherShow := newBookingMade(uuid.New(), day)
+
+		for _, event := range []*entities.BookingMade{morning, nextDay, noon, otherShow} {
+			require.NoError(t, repository.OnBookingMade(ctx, event))
+		}
+
+		bookings, err := repository.All(ctx, entities.OpsBookingsFilter{ShowID: &showID})
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]uuid.UUID{nextDay.BookingID, noon.BookingID, morning.BookingID},
+			opsBookingIDs(bookings),
+			"the most recent bookings should be first",
+		)
+
+		bookings, err = repository.All(ctx, entities.OpsBookingsFilter{ShowID: &showID, Date: &day})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{noon.BookingID, morning.BookingID}, opsBookingIDs(bookings))
+	})
+}
+
+func opsBookingIDs(bookings []entities.OpsBooking) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(bookings))
+	for _, booking := range bookings {
+		ids = append(ids, booking.BookingID)
+	}
+
+	return ids
+}
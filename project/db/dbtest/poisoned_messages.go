@@ -0,0 +1,107 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PoisonedMessageRepository interface {
+	Save(ctx context.Context, msg entities.PoisonedMessage) error
+	All(ctx context.Context, filter entities.PoisonedMessagesFilter) ([]entities.PoisonedMessage, error)
+	ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error)
+	Delete(ctx context.Context, messageUUID string) error
+}
+
+func TestPoisonedMessageRepository(t *testing.T, repository PoisonedMessageRepository) {
+	ctx := context.Background()
+
+	newMessage := func(topic string, handlerName string, poisonedAt time.Time) entities.PoisonedMessage {
+		return entities.PoisonedMessage{
+			MessageUUID: uuid.NewString(),
+			Topic:       topic,
+			HandlerName: handlerName,
+			Reason:      "spreadsheets API is down",
+			Payload:     `{"ticket_id":"1"}`,
+			Metadata:    map[string]string{"name": topic},
+			PoisonedAt:  poisonedAt,
+		}
+	}
+
+	t.Run("poisoned again", func(t *testing.T) {
+		msg := newMessage("TicketBookingConfirmed", "handler-"+uuid.NewString(), now())
+		require.NoError(t, repository.Save(ctx, msg))
+
+		// the message can be poisoned again after a requeue
+		poisonedAgain := msg
+		poisonedAgain.Reason = "spreadsheets API is still down"
+		poisonedAgain.Payload = `{"ticket_id":"2"}`
+		poisonedAgain.PoisonedAt = msg.PoisonedAt.Add(time.Minute)
+		require.NoError(t, repository.Save(ctx, poisonedAgain))
+
+		stored, err := repository.ByUUID(ctx, msg.MessageUUID)
+		require.NoError(t, err)
+		assert.Equal(t, msg.Payload, stored.Payload, "payload should not change when the message is poisoned again")
+		assert.Equal(t, msg.Metadata, stored.Metadata)
+		assert.Equal(t, poisonedAgain.Reason, stored.Reason)
+		assertTime(t, poisonedAgain.PoisonedAt, &stored.PoisonedAt)
+
+		require.NoError(t, repository.Delete(ctx, msg.MessageUUID))
+		assert.ErrorIs(t, repository.Delete(ctx, msg.MessageUUID), entities.ErrPoisonedMessageNotFound)
+
+		_, err = repository.ByUUID(ctx, msg.MessageUUID)
+		assert.ErrorIs(t, err, entities.ErrPoisonedMessageNotFound)
+	})
+
+	t.Run("paging and filters", func(t *testing.T) {
+		handlerName := "handler-" + uuid.NewString()
+		poisonedAt := now()
+
+		var messages []entities.PoisonedMessage
+		for i := 0; i < 5; i++ {
+			messages = append(messages, newMessage("TicketBookingConfirmed", handlerName, poisonedAt.Add(time.Duration(i)*time.Second)))
+		}
+		otherTopic := newMessage("TicketRefunded", handlerName, poisonedAt)
+
+		// saved in reverse order, so the order of insertion doesn't match the expected one
+		for i := len(messages) - 1; i >= 0; i-- {
+			require.NoError(t, repository.Save(ctx, messages[i]))
+		}
+		require.NoError(t, repository.Save(ctx, otherTopic))
+		require.NoError(t, repository.Save(ctx, newMessage("TicketBookingConfirmed", "handler-"+uuid.NewString(), poisonedAt)))
+
+		all, err := repository.All(ctx, entities.PoisonedMessagesFilter{HandlerName: handlerName, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, all, len(messages)+1)
+
+		filter := entities.PoisonedMessagesFilter{Topic: "TicketBookingConfirmed", HandlerName: handlerName, Limit: 3}
+		firstPage, err := repository.All(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, messageUUIDs(messages[:3]), messageUUIDs(firstPage))
+
+		filter.Offset = 3
+		secondPage, err := repository.All(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, messageUUIDs(messages[3:]), messageUUIDs(secondPage))
+
+		filter.Offset = 6
+		afterLastPage, err := repository.All(ctx, filter)
+		require.NoError(t, err)
+		assert.Empty(t, afterLastPage)
+	})
+}
+
+func messageUUIDs(messages []entities.PoisonedMessage) []string {
+	uuids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		uuids = append(uuids, msg.MessageUUID)
+	}
+
+	return uuids
+}
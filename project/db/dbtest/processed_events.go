@@ -0,0 +1,35 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ProcessedEventsRepository interface {
+	IsProcessed(ctx context.Context, handlerName string, idempotencyKey string) (bool, error)
+	MarkProcessed(ctx context.Context, handlerName string, idempotencyKey string) error
+}
+
+func TestProcessedEventsRepository(t *testing.T, repository ProcessedEventsRepository) {
+	ctx := context.Background()
+	idempotencyKey := uuid.NewString()
+
+	processed, err := repository.IsProcessed(ctx, "IssueReceipt", idempotencyKey)
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	require.NoError(t, repository.MarkProcessed(ctx, "IssueReceipt", idempotencyKey))
+	require.NoError(t, repository.MarkProcessed(ctx, "IssueReceipt", idempotencyKey), "marking an event twice should be a no-op")
+
+	processed, err = repository.IsProcessed(ctx, "IssueReceipt", idempotencyKey)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	processed, err = repository.IsProcessed(ctx, "PrintTicket", idempotencyKey)
+	require.NoError(t, err)
+	assert.False(t, processed, "events are processed separately by every handler")
+}
@@ -0,0 +1,41 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ReceiptRepository interface {
+	Save(ctx context.Context, receipt entities.Receipt) error
+	ByTicketID(ctx context.Context, ticketID string) (entities.Receipt, error)
+}
+
+func TestReceiptRepository(t *testing.T, repository ReceiptRepository) {
+	ctx := context.Background()
+
+	receipt := entities.Receipt{
+		TicketID:      uuid.NewString(),
+		ReceiptNumber: "receipt-" + uuid.NewString(),
+		IssuedAt:      now(),
+	}
+	require.NoError(t, repository.Save(ctx, receipt))
+
+	// saving a receipt of the same ticket again keeps the first one
+	duplicate := receipt
+	duplicate.ReceiptNumber = "receipt-" + uuid.NewString()
+	require.NoError(t, repository.Save(ctx, duplicate))
+
+	stored, err := repository.ByTicketID(ctx, receipt.TicketID)
+	require.NoError(t, err)
+	assert.Equal(t, receipt.ReceiptNumber, stored.ReceiptNumber)
+	assertTime(t, receipt.IssuedAt, &stored.IssuedAt)
+
+	_, err = repository.ByTicketID(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, entities.ErrReceiptNotFound)
+}
@@ -0,0 +1,78 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ScheduledMessageRepository interface {
+	Schedule(ctx context.Context, msg entities.ScheduledMessage) error
+	DeliverDue(ctx context.Context, limit int, deliver func(msg entities.ScheduledMessage) error) (int, error)
+}
+
+// TestScheduledMessageRepository expects a repository without any scheduled messages.
+func TestScheduledMessageRepository(t *testing.T, repository ScheduledMessageRepository) {
+	ctx := context.Background()
+
+	newMessage := func(deliverAt time.Time) entities.ScheduledMessage {
+		return entities.ScheduledMessage{
+			ID:          uuid.New(),
+			MessageUUID: uuid.NewString(),
+			Topic:       "TicketBookingConfirmed",
+			Payload:     []byte(`{"ticket_id":"1"}`),
+			Metadata:    map[string]string{"retry_attempt": "1"},
+			DeliverAt:   deliverAt,
+		}
+	}
+
+	var delivered []entities.ScheduledMessage
+	deliver := func(msg entities.ScheduledMessage) error {
+		delivered = append(delivered, msg)
+		return nil
+	}
+
+	later := newMessage(now().Add(-time.Second))
+	earlier := newMessage(now().Add(-time.Minute))
+	notDue := newMessage(now().Add(time.Hour))
+
+	for _, msg := range []entities.ScheduledMessage{later, notDue, earlier} {
+		require.NoError(t, repository.Schedule(ctx, msg))
+	}
+
+	duplicate := later
+	duplicate.Topic = "TicketRefunded"
+	require.NoError(t, repository.Schedule(ctx, duplicate))
+
+	count, err := repository.DeliverDue(ctx, 10, func(msg entities.ScheduledMessage) error {
+		return errors.New("broker is down")
+	})
+	require.Error(t, err)
+	assert.Zero(t, count)
+
+	count, err = repository.DeliverDue(ctx, 1, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repository.DeliverDue(ctx, 10, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repository.DeliverDue(ctx, 10, deliver)
+	require.NoError(t, err)
+	assert.Zero(t, count, "delivered messages should be removed")
+
+	require.Len(t, delivered, 2, "messages which failed to deliver should be delivered again")
+	assert.Equal(t, earlier.MessageUUID, delivered[0].MessageUUID, "messages should be delivered in order of delivery time")
+	assert.Equal(t, later.MessageUUID, delivered[1].MessageUUID)
+	assert.Equal(t, later.Topic, delivered[1].Topic)
+	assert.Equal(t, later.Payload, delivered[1].Payload)
+	assert.Equal(t, later.Metadata, delivered[1].Metadata)
+}
@@ -0,0 +1,119 @@
+package dbtest
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ShowRepository interface {
+	Create(ctx context.Context, show entities.Show) error
+	All(ctx context.Context, filter entities.ShowsFilter) ([]entities.ShowWithAvailability, error)
+	ByID(ctx context.Context, id uuid.UUID) (entities.ShowWithAvailability, error)
+}
+
+func TestShowRepository(t *testing.T, repository ShowRepository) {
+	ctx := context.Background()
+	startTime := now().Add(24 * time.Hour).Truncate(time.Second)
+
+	newShow := func(venue string, startTime time.Time) entities.Show {
+		return entities.Show{
+			ID:              uuid.New(),
+			DeadNationID:    uuid.New(),
+			NumberOfTickets: 10,
+			StartTime:       startTime,
+			Title:           "Show",
+			Venue:           venue,
+			TicketPrice:     entities.Price{Amount: entities.MustNewDecimal("25.00"), Currency: "EUR"},
+		}
+	}
+
+	t.Run("create is idempotent", func(t *testing.T) {
+		show := newShow("venue-"+uuid.NewString(), startTime)
+		require.NoError(t, repository.Create(ctx, show))
+
+		duplicate := show
+		duplicate.Title = "Other show"
+		require.NoError(t, repository.Create(ctx, duplicate))
+
+		stored, err := repository.ByID(ctx, show.ID)
+		require.NoError(t, err)
+		assert.Equal(t, show.Title, stored.Title)
+		assert.Equal(t, show.NumberOfTickets, stored.RemainingTickets)
+		assert.Equal(t, "25.00", stored.TicketPrice.Amount.String())
+
+		_, err = repository.ByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, entities.ErrShowNotFound)
+	})
+
+	t.Run("paging and filters", func(t *testing.T) {
+		venue := "venue-" + uuid.NewString()
+
+		var shows []entities.Show
+		for i := 0; i < 5; i++ {
+			shows = append(shows, newShow(venue, startTime.Add(time.Duration(i)*time.Hour)))
+		}
+		// shows starting at the same time are ordered by ID
+		shows = append(shows, newShow(venue, shows[2].StartTime))
+
+		for _, show := range shows {
+			require.NoError(t, repository.Create(ctx, show))
+		}
+		require.NoError(t, repository.Create(ctx, newShow("venue-"+uuid.NewString(), startTime)))
+
+		sort.Slice(shows, func(i, j int) bool {
+			if !shows[i].StartTime.Equal(shows[j].StartTime) {
+				return shows[i].StartTime.Before(shows[j].StartTime)
+			}
+			return shows[i].ID.String() < shows[j].ID.String()
+		})
+
+		all, err := repository.All(ctx, entities.ShowsFilter{Venue: venue, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, showIDs(shows), showWithAvailabilityIDs(all))
+
+		firstPage, err := repository.All(ctx, entities.ShowsFilter{Venue: venue, Limit: 4})
+		require.NoError(t, err)
+		assert.Equal(t, showIDs(shows[:4]), showWithAvailabilityIDs(firstPage))
+
+		secondPage, err := repository.All(ctx, entities.ShowsFilter{Venue: venue, Limit: 4, Offset: 4})
+		require.NoError(t, err)
+		assert.Equal(t, showIDs(shows[4:]), showWithAvailabilityIDs(secondPage))
+
+		afterLastPage, err := repository.All(ctx, entities.ShowsFilter{Venue: venue, Limit: 4, Offset: 8})
+		require.NoError(t, err)
+		assert.Empty(t, afterLastPage)
+
+		// both bounds are inclusive
+		from := shows[1].StartTime
+		to := shows[4].StartTime
+		between, err := repository.All(ctx, entities.ShowsFilter{Venue: venue, StartFrom: &from, StartTo: &to, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, showIDs(shows[1:5]), showWithAvailabilityIDs(between))
+	})
+}
+
+func showIDs(shows []entities.Show) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(shows))
+	for _, show := range shows {
+		ids = append(ids, show.ID)
+	}
+
+	return ids
+}
+
+func showWithAvailabilityIDs(shows []entities.ShowWithAvailability) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(shows))
+	for _, show := range shows {
+		ids = append(ids, show.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,143 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TicketRepository interface {
+	Save(ctx context.Context, ticket *entities.Ticket) error
+	Cancel(ctx context.Context, ticket *entities.Ticket) error
+	UpdateStatus(ctx context.Context, ticketID string, status entities.TicketStatus) error
+	CheckIn(ctx context.Context, ticketID string) error
+	MarkPrinted(ctx context.Context, ticketID string, fileName string) error
+	ByID(ctx context.Context, ticketID string) (entities.Ticket, error)
+	FileName(ctx context.Context, ticketID string) (string, error)
+	All(ctx context.Context, filter entities.TicketsFilter) ([]entities.Ticket, error)
+}
+
+func TestTicketRepository(t *testing.T, repository TicketRepository) {
+	ctx := context.Background()
+
+	newTicket := func() *entities.Ticket {
+		return &entities.Ticket{
+			ID:            uuid.NewString(),
+			Price:         entities.Price{Amount: entities.MustNewDecimal("50.00"), Currency: "EUR"},
+			CustomerEmail: "customer@example.com",
+		}
+	}
+
+	t.Run("save is idempotent", func(t *testing.T) {
+		ticket := newTicket()
+		require.NoError(t, repository.Save(ctx, ticket))
+
+		redelivered := *ticket
+		redelivered.CustomerEmail = "other@example.com"
+		require.NoError(t, repository.Save(ctx, &redelivered))
+
+		stored, err := repository.ByID(ctx, ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketStatusConfirmed, stored.Status)
+		assert.Equal(t, ticket.CustomerEmail, stored.CustomerEmail)
+		assert.Equal(t, "50.00", stored.Price.Amount.String())
+		assert.Equal(t, "EUR", stored.Price.Currency)
+	})
+
+	t.Run("cancel before confirmation", func(t *testing.T) {
+		ticket := newTicket()
+		require.NoError(t, repository.Cancel(ctx, ticket))
+		require.NoError(t, repository.Save(ctx, ticket))
+
+		stored, err := repository.ByID(ctx, ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketStatusCanceled, stored.Status, "late confirmation should not overwrite the cancellation")
+	})
+
+	t.Run("status transitions", func(t *testing.T) {
+		ticket := newTicket()
+		require.NoError(t, repository.Save(ctx, ticket))
+
+		require.NoError(t, repository.Cancel(ctx, ticket))
+		require.NoError(t, repository.Cancel(ctx, ticket), "redelivered cancellation should be a no-op")
+		require.NoError(t, repository.UpdateStatus(ctx, ticket.ID, entities.TicketStatusRefunded))
+
+		err := repository.UpdateStatus(ctx, ticket.ID, entities.TicketStatusConfirmed)
+		assert.ErrorIs(t, err, entities.ErrInvalidTicketStatusTransition)
+		err = repository.Cancel(ctx, ticket)
+		assert.ErrorIs(t, err, entities.ErrInvalidTicketStatusTransition)
+
+		stored, err := repository.ByID(ctx, ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketStatusRefunded, stored.Status)
+
+		err = repository.UpdateStatus(ctx, uuid.NewString(), entities.TicketStatusCanceled)
+		assert.ErrorIs(t, err, entities.ErrTicketNotFound)
+	})
+
+	t.Run("check in", func(t *testing.T) {
+		confirmed := newTicket()
+		require.NoError(t, repository.Save(ctx, confirmed))
+		require.NoError(t, repository.CheckIn(ctx, confirmed.ID))
+
+		stored, err := repository.ByID(ctx, confirmed.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.CheckedIn)
+
+		canceled := newTicket()
+		require.NoError(t, repository.Cancel(ctx, canceled))
+		assert.ErrorIs(t, repository.CheckIn(ctx, canceled.ID), entities.ErrInvalidTicketStatusTransition)
+
+		assert.ErrorIs(t, repository.CheckIn(ctx, uuid.NewString()), entities.ErrTicketNotFound)
+	})
+
+	t.Run("print", func(t *testing.T) {
+		ticket := newTicket()
+		require.NoError(t, repository.Save(ctx, ticket))
+
+		_, err := repository.FileName(ctx, ticket.ID)
+		assert.ErrorIs(t, err, entities.ErrTicketNotPrinted)
+
+		fileName := ticket.ID + "-ticket.html"
+		require.NoError(t, repository.MarkPrinted(ctx, ticket.ID, fileName))
+
+		stored, err := repository.FileName(ctx, ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fileName, stored)
+
+		assert.ErrorIs(t, repository.MarkPrinted(ctx, uuid.NewString(), fileName), entities.ErrTicketNotFound)
+		_, err = repository.FileName(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, entities.ErrTicketNotFound)
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		confirmed := newTicket()
+		require.NoError(t, repository.Save(ctx, confirmed))
+		canceled := newTicket()
+		require.NoError(t, repository.Cancel(ctx, canceled))
+
+		defaultTickets, err := repository.All(ctx, entities.TicketsFilter{})
+		require.NoError(t, err)
+		assert.Contains(t, ticketIDs(defaultTickets), confirmed.ID, "confirmed tickets should be returned by default")
+		assert.NotContains(t, ticketIDs(defaultTickets), canceled.ID)
+
+		canceledTickets, err := repository.All(ctx, entities.TicketsFilter{Status: entities.TicketStatusCanceled})
+		require.NoError(t, err)
+		assert.Contains(t, ticketIDs(canceledTickets), canceled.ID)
+		assert.NotContains(t, ticketIDs(canceledTickets), confirmed.ID)
+	})
+}
+
+func ticketIDs(tickets []entities.Ticket) []string {
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.ID)
+	}
+
+	return ids
+}
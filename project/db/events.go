@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tickets/entities"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEventRepository(db *pgxpool.Pool) *EventRepository {
+	if db == nil {
+		panic("db passed to 'NewEventRepository()' is nil!")
+	}
+	return &EventRepository{db: db}
+}
+
+func (repository *EventRepository) Append(ctx context.Context, event entities.StoredEvent) error {
+	q := `INSERT INTO events (
+		 event_id,
+		 event_name,
+		 version,
+		 correlation_id,
+		 published_at,
+		 payload
+		 ) VALUES ($1, $2, $3, $4, $5, $6)
+		   ON CONFLICT DO NOTHING;
+	`
+
+	_, err := repository.db.Exec(
+		ctx,
+		q,
+		event.EventID,
+		event.EventName,
+		event.Version,
+		event.CorrelationID,
+		event.PublishedAt,
+		string(event.Payload),
+	)
+	if err != nil {
+		return fmt.Errorf("error appending event: %w", err)
+	}
+
+	return nil
+}
+
+func (repository *EventRepository) All(ctx context.Context, filter entities.StoredEventsFilter) ([]entities.StoredEvent, error) {
+	var conditions []string
+	var args []any
+
+	if len(filter.EventNames) > 0 {
+		args = append(args, filter.EventNames)
+		conditions = append(conditions, fmt.Sprintf("event_name = ANY($%d)", len(args)))
+	}
+	if filter.CorrelationID != "" {
+		args = append(args, filter.CorrelationID)
+		conditions = append(conditions, fmt.Sprintf("correlation_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("published_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("published_at <= $%d", len(args)))
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.PublishedAt, filter.After.EventID)
+		conditions = append(conditions, fmt.Sprintf("(published_at, event_id) > ($%d::timestamp, $%d::uuid)", len(args)-1, len(args)))
+	}
+
+	q := `SELECT event_id, event_name, version, correlation_id, published_at, payload FROM events`
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	q += fmt.Sprintf(" ORDER BY published_at, event_id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := repository.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entities.StoredEvent{}
+	for rows.Next() {
+		var event entities.StoredEvent
+		var payload []byte
+
+		err := rows.Scan(
+			&event.EventID,
+			&event.EventName,
+			&event.Version,
+			&event.CorrelationID,
+			&event.PublishedAt,
+			&payload,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning event row: %w", err)
+		}
+		event.Payload = payload
+
+		events = append(events, event)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error iterating over event rows: %w", rows.Err())
+	}
+
+	return events, nil
+}
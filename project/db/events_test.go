@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestEventRepository(t *testing.T) {
+	dbtest.TestEventRepository(t, NewEventRepository(getDb()))
+}
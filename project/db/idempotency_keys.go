@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// idempotencyKeyLockTimeout is how long a request can be in progress before its key is considered abandoned,
+// for example when the service was killed while handling it.
+const idempotencyKeyLockTimeout = time.Minute
+
+type IdempotencyKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyKeyRepository(db *pgxpool.Pool) *IdempotencyKeyRepository {
+	if db == nil {
+		panic("db passed to 'NewIdempotencyKeyRepository()' is nil!")
+	}
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Start locks the idempotency key for the request.
+// When the key was already used for the same request and it's completed, the stored response is returned.
+// A nil response means the request should be handled.
+func (repository *IdempotencyKeyRepository) Start(
+	ctx context.Context,
+	idempotencyKey string,
+	requestHash string,
+) (*entities.IdempotentResponse, error) {
+	var response *entities.IdempotentResponse
+
+	err := pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(
+			ctx,
+			`INSERT INTO http_idempotency_keys (idempotency_key, request_hash, locked_at)
+			VALUES ($1, $2, now())
+			ON CONFLICT DO NOTHING`,
+			idempotencyKey,
+			requestHash,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving idempotency key: %w", err)
+		}
+
+		if tag.RowsAffected() == 1 {
+			return nil
+		}
+
+		var storedHash string
+		var abandoned bool
+		var completedAt *time.Time
+		var statusCode *int
+		var contentType *string
+		var body []byte
+
+		err = tx.QueryRow(
+			ctx,
+			`SELECT
+				request_hash,
+				locked_at < now() - make_interval(secs => $2),
+				completed_at,
+				response_status_code,
+				response_content_type,
+				response_body
+			FROM http_idempotency_keys
+			WHERE idempotency_key = $1
+			FOR UPDATE`,
+			idempotencyKey,
+			idempotencyKeyLockTimeout.Seconds(),
+		).Scan(&storedHash, &abandoned, &completedAt, &statusCode, &contentType, &body)
+		if err != nil {
+			return fmt.Errorf("error fetching idempotency key: %w", err)
+		}
+
+		if storedHash != requestHash {
+			return entities.ErrIdempotencyKeyReused
+		}
+
+		if completedAt != nil {
+			response = &entities.IdempotentResponse{Body: body}
+			if statusCode != nil {
+				response.StatusCode = *statusCode
+			}
+			if contentType != nil {
+				response.ContentType = *contentType
+			}
+			return nil
+		}
+
+		if !abandoned {
+			return entities.ErrIdempotencyKeyInProgress
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE http_idempotency_keys SET locked_at = now() WHERE idempotency_key = $1", idempotencyKey)
+		if err != nil {
+			return fmt.Errorf("error locking abandoned idempotency key: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (repository *IdempotencyKeyRepository) Complete(
+	ctx context.Context,
+	idempotencyKey string,
+	response entities.IdempotentResponse,
+) error {
+	_, err := repository.db.Exec(
+		ctx,
+		`UPDATE http_idempotency_keys SET
+			response_status_code = $1,
+			response_content_type = $2,
+			response_body = $3,
+			completed_at = now()
+		WHERE idempotency_key = $4`,
+		response.StatusCode,
+		response.ContentType,
+		response.Body,
+		idempotencyKey,
+	)
+	if err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the key of a failed request, so it can be retried.
+func (repository *IdempotencyKeyRepository) Release(ctx context.Context, idempotencyKey string) error {
+	_, err := repository.db.Exec(
+		ctx,
+		"DELETE FROM http_idempotency_keys WHERE idempotency_key = $1 AND completed_at IS NULL",
+		idempotencyKey,
+	)
+	if err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
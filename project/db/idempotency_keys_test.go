@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	dbtest.TestIdempotencyKeyRepository(t, NewIdempotencyKeyRepository(getDb()))
+}
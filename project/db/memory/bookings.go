@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+)
+
+type EventBus interface {
+	Publish(ctx context.Context, event any) error
+}
+
+// BookingRepository stores bookings in the ShowRepository, so the availability of shows includes them.
+//
+// There is no outbox in memory, so BookingMade is published right after the booking is stored,
+// and the booking is removed when publishing fails.
+type BookingRepository struct {
+	shows    *ShowRepository
+	eventBus EventBus
+}
+
+func NewBookingRepository(shows *ShowRepository, eventBus EventBus) *BookingRepository {
+	if shows == nil {
+		panic("shows passed to 'NewBookingRepository()' is nil!")
+	}
+	if eventBus == nil {
+		panic("eventBus passed to 'NewBookingRepository()' is nil!")
+	}
+	return &BookingRepository{shows: shows, eventBus: eventBus}
+}
+
+func (r *BookingRepository) Create(ctx context.Context, b entities.Booking) error {
+	if b.NumberOfTickets <= 0 {
+		return entities.ErrInvalidTicketsBooking
+	}
+
+	if err := r.book(b); err != nil {
+		return err
+	}
+
+	err := r.eventBus.Publish(ctx, entities.BookingMade{
+		Header:          entities.NewEventHeader(),
+		BookingID:       b.ID,
+		NumberOfTickets: b.NumberOfTickets,
+		CustomerEmail:   b.CustomerEmail,
+		ShowId:          b.ShowID,
+	})
+	if err != nil {
+		r.shows.lock.Lock()
+		delete(r.shows.bookings, b.ID)
+		r.shows.lock.Unlock()
+
+		return fmt.Errorf("could not publish event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BookingRepository) book(b entities.Booking) error {
+	r.shows.lock.Lock()
+	defer r.shows.lock.Unlock()
+
+	show, ok := r.shows.shows[b.ShowID]
+	if !ok {
+		return entities.ErrShowNotFound
+	}
+
+	if r.shows.withAvailability(show).RemainingTickets < b.NumberOfTickets {
+		return entities.ErrNotEnoughTicketsLeft
+	}
+
+	if _, ok := r.shows.bookings[b.ID]; ok {
+		return fmt.Errorf("error: failed to insert booking: booking %s already exists", b.ID)
+	}
+	r.shows.bookings[b.ID] = b
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type eventBusMock struct {
+	lock   sync.Mutex
+	events []any
+	err    error
+}
+
+func (m *eventBusMock) Publish(ctx context.Context, event any) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+func TestBookingIsRemovedWhenEventIsNotPublished(t *testing.T) {
+	ctx := context.Background()
+
+	shows := NewShowRepository()
+	show := entities.Show{ID: uuid.New(), NumberOfTickets: 1}
+	require.NoError(t, shows.Create(ctx, show))
+
+	repository := NewBookingRepository(shows, &eventBusMock{err: errors.New("broker is down")})
+
+	err := repository.Create(ctx, entities.Booking{ID: uuid.New(), ShowID: show.ID, NumberOfTickets: 1})
+	require.Error(t, err)
+
+	availability, err := shows.ByID(ctx, show.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, availability.RemainingTickets)
+}
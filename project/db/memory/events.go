@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"tickets/entities"
+)
+
+type EventRepository struct {
+	lock   sync.Mutex
+	events map[string]entities.StoredEvent
+}
+
+func NewEventRepository() *EventRepository {
+	return &EventRepository{events: map[string]entities.StoredEvent{}}
+}
+
+func (repository *EventRepository) Append(ctx context.Context, event entities.StoredEvent) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.events[event.EventID]; !ok {
+		repository.events[event.EventID] = event
+	}
+
+	return nil
+}
+
+func (repository *EventRepository) All(ctx context.Context, filter entities.StoredEventsFilter) ([]entities.StoredEvent, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	events := []entities.StoredEvent{}
+	for _, event := range repository.events {
+		if len(filter.EventNames) > 0 && !slices.Contains(filter.EventNames, event.EventName) {
+			continue
+		}
+		if filter.CorrelationID != "" && event.CorrelationID != filter.CorrelationID {
+			continue
+		}
+		if filter.From != nil && event.PublishedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && event.PublishedAt.After(*filter.To) {
+			continue
+		}
+		if filter.After != nil && !storedEventAfter(event, *filter.After) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return storedEventAfter(events[j], events[i].Cursor())
+	})
+
+	return page(events, filter.Limit, filter.Offset), nil
+}
+
+func storedEventAfter(event entities.StoredEvent, cursor entities.StoredEventCursor) bool {
+	if !event.PublishedAt.Equal(cursor.PublishedAt) {
+		return event.PublishedAt.After(cursor.PublishedAt)
+	}
+	return event.EventID > cursor.EventID
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"tickets/entities"
+)
+
+// idempotencyKeyLockTimeout is how long a request can be in progress before its key is considered abandoned.
+const idempotencyKeyLockTimeout = time.Minute
+
+type IdempotencyKeyRepository struct {
+	lock sync.Mutex
+	keys map[string]idempotencyKey
+}
+
+type idempotencyKey struct {
+	requestHash string
+	lockedAt    time.Time
+	// response is set when the request is completed
+	response *entities.IdempotentResponse
+}
+
+func NewIdempotencyKeyRepository() *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{keys: map[string]idempotencyKey{}}
+}
+
+// Start locks the idempotency key for the request.
+// When the key was already used for the same request and it's completed, the stored response is returned.
+// A nil response means the request should be handled.
+func (repository *IdempotencyKeyRepository) Start(
+	ctx context.Context,
+	key string,
+	requestHash string,
+) (*entities.IdempotentResponse, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	stored, ok := repository.keys[key]
+	if !ok {
+		repository.keys[key] = idempotencyKey{requestHash: requestHash, lockedAt: time.Now()}
+		return nil, nil
+	}
+
+	if stored.requestHash != requestHash {
+		return nil, entities.ErrIdempotencyKeyReused
+	}
+
+	if stored.response != nil {
+		response := *stored.response
+		return &response, nil
+	}
+
+	if time.Since(stored.lockedAt) < idempotencyKeyLockTimeout {
+		return nil, entities.ErrIdempotencyKeyInProgress
+	}
+
+	stored.lockedAt = time.Now()
+	repository.keys[key] = stored
+
+	return nil, nil
+}
+
+func (repository *IdempotencyKeyRepository) Complete(
+	ctx context.Context,
+	key string,
+	response entities.IdempotentResponse,
+) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	stored, ok := repository.keys[key]
+	if !ok {
+		return nil
+	}
+
+	stored.response = &response
+	repository.keys[key] = stored
+
+	return nil
+}
+
+// Release removes the key of a failed request, so it can be retried.
+func (repository *IdempotencyKeyRepository) Release(ctx context.Context, key string) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if stored, ok := repository.keys[key]; ok && stored.response == nil {
+		delete(repository.keys, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+// Package memory has in-memory implementations of the repositories from the db package.
+//
+// They keep the semantics of the Postgres repositories (conflicts, status transitions, filters and ordering),
+// so the service can run without any infrastructure, for example in component tests.
+// The test cases in db/dbtest are run against both implementations to check that.
+// The data is lost when the service stops.
+package memory
+
+// page returns the part of items selected by limit and offset, like LIMIT and OFFSET in SQL.
+func page[T any](items []T, limit int, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+
+	if limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
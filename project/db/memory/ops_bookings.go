@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+)
+
+// OpsBookingsRepository stores the operations read model.
+//
+// Like in the Postgres repository, every event only fills in its own fields, and already set timestamps are never overwritten.
+type OpsBookingsRepository struct {
+	lock     sync.Mutex
+	bookings map[uuid.UUID]entities.OpsBooking
+	tickets  map[string]opsTicket
+}
+
+// opsTicket is a ticket with its booking, which is unknown until the ticket is confirmed.
+type opsTicket struct {
+	entities.OpsTicket
+	bookingID *uuid.UUID
+}
+
+func NewOpsBookingsRepository() *OpsBookingsRepository {
+	return &OpsBookingsRepository{
+		bookings: map[uuid.UUID]entities.OpsBooking{},
+		tickets:  map[string]opsTicket{},
+	}
+}
+
+func (repository *OpsBookingsRepository) OnBookingMade(ctx context.Context, event *entities.BookingMade) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	showID := event.ShowId
+	bookedAt := event.Header.PublishedAt
+
+	repository.bookings[event.BookingID] = entities.OpsBooking{
+		BookingID:       event.BookingID,
+		ShowID:          &showID,
+		BookedAt:        &bookedAt,
+		CustomerEmail:   event.CustomerEmail,
+		NumberOfTickets: event.NumberOfTickets,
+	}
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) OnTicketBookingConfirmed(ctx context.Context, event *entities.TicketBookingConfirmed) error {
+	bookingID, err := uuid.Parse(event.BookingID)
+	if err != nil {
+		return fmt.Errorf("invalid booking id %q: %w", event.BookingID, err)
+	}
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	// the booking may not be projected yet, its details will be filled in by BookingMade
+	if _, ok := repository.bookings[bookingID]; !ok {
+		repository.bookings[bookingID] = entities.OpsBooking{BookingID: bookingID}
+	}
+
+	ticket := repository.ticket(event.TicketID)
+	ticket.bookingID = &bookingID
+	ticket.Price = event.Price
+	ticket.CustomerEmail = event.CustomerEmail
+	ticket.ConfirmedAt = coalesce(ticket.ConfirmedAt, event.Header.PublishedAt)
+	repository.tickets[event.TicketID] = ticket
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) OnTicketBookingCanceled(ctx context.Context, event *entities.TicketBookingCanceled) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket := repository.ticket(event.TicketID)
+	ticket.CanceledAt = coalesce(ticket.CanceledAt, event.Header.PublishedAt)
+	repository.tickets[event.TicketID] = ticket
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) OnTicketRefunded(ctx context.Context, event *entities.TicketRefunded) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket := repository.ticket(event.TicketID)
+	ticket.RefundedAt = coalesce(ticket.RefundedAt, event.Header.PublishedAt)
+	repository.tickets[event.TicketID] = ticket
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) OnTicketPrinted(ctx context.Context, event *entities.TicketPrinted) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket := repository.ticket(event.TicketID)
+	ticket.PrintedAt = coalesce(ticket.PrintedAt, event.Header.PublishedAt)
+	ticket.PrintedFileName = event.FileName
+	repository.tickets[event.TicketID] = ticket
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) ticket(ticketID string) opsTicket {
+	ticket, ok := repository.tickets[ticketID]
+	if !ok {
+		ticket.TicketID = ticketID
+	}
+
+	return ticket
+}
+
+func coalesce(current *time.Time, at time.Time) *time.Time {
+	if current != nil {
+		return current
+	}
+
+	return &at
+}
+
+func (repository *OpsBookingsRepository) All(ctx context.Context, filter entities.OpsBookingsFilter) ([]entities.OpsBooking, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	bookings := []entities.OpsBooking{}
+	for _, booking := range repository.bookings {
+		if filter.Date != nil {
+			if booking.BookedAt == nil || booking.BookedAt.UTC().Format(time.DateOnly) != filter.Date.Format(time.DateOnly) {
+				continue
+			}
+		}
+		if filter.ShowID != nil {
+			if booking.ShowID == nil || *booking.ShowID != *filter.ShowID {
+				continue
+			}
+		}
+
+		bookings = append(bookings, repository.withTickets(booking))
+	}
+
+	// bookings without booked_at go last, like NULLS LAST
+	sort.Slice(bookings, func(i, j int) bool {
+		a, b := bookings[i].BookedAt, bookings[j].BookedAt
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.After(*b)
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		default:
+			return bookings[i].BookingID.String() < bookings[j].BookingID.String()
+		}
+	})
+
+	return bookings, nil
+}
+
+func (repository *OpsBookingsRepository) ByID(ctx context.Context, bookingID uuid.UUID) (entities.OpsBooking, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	booking, ok := repository.bookings[bookingID]
+	if !ok {
+		return entities.OpsBooking{}, entities.ErrOpsBookingNotFound
+	}
+
+	return repository.withTickets(booking), nil
+}
+
+func (repository *OpsBookingsRepository) withTickets(booking entities.OpsBooking) entities.OpsBooking {
+	booking.Tickets = []entities.OpsTicket{}
+
+	for _, ticket := range repository.tickets {
+		if ticket.bookingID == nil || *ticket.bookingID != booking.BookingID {
+			continue
+		}
+
+		t := ticket.OpsTicket
+		t.Status = t.CurrentStatus()
+		booking.Tickets = append(booking.Tickets, t)
+	}
+
+	sort.Slice(booking.Tickets, func(i, j int) bool {
+		return booking.Tickets[i].TicketID < booking.Tickets[j].TicketID
+	})
+
+	return booking
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"tickets/entities"
+)
+
+type PoisonedMessageRepository struct {
+	lock     sync.Mutex
+	messages map[string]entities.PoisonedMessage
+}
+
+func NewPoisonedMessageRepository() *PoisonedMessageRepository {
+	return &PoisonedMessageRepository{messages: map[string]entities.PoisonedMessage{}}
+}
+
+func (repository *PoisonedMessageRepository) Save(ctx context.Context, msg entities.PoisonedMessage) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	// the payload of a message doesn't change when it's poisoned again
+	if stored, ok := repository.messages[msg.MessageUUID]; ok {
+		msg.Payload = stored.Payload
+	}
+	repository.messages[msg.MessageUUID] = msg
+
+	return nil
+}
+
+func (repository *PoisonedMessageRepository) All(ctx context.Context, filter entities.PoisonedMessagesFilter) ([]entities.PoisonedMessage, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	messages := []entities.PoisonedMessage{}
+	for _, msg := range repository.messages {
+		if filter.Topic != "" && msg.Topic != filter.Topic {
+			continue
+		}
+		if filter.HandlerName != "" && msg.HandlerName != filter.HandlerName {
+			continue
+		}
+
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].PoisonedAt.Equal(messages[j].PoisonedAt) {
+			return messages[i].PoisonedAt.Before(messages[j].PoisonedAt)
+		}
+		return messages[i].MessageUUID < messages[j].MessageUUID
+	})
+
+	return page(messages, filter.Limit, filter.Offset), nil
+}
+
+func (repository *PoisonedMessageRepository) ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	msg, ok := repository.messages[messageUUID]
+	if !ok {
+		return entities.PoisonedMessage{}, entities.ErrPoisonedMessageNotFound
+	}
+
+	return msg, nil
+}
+
+func (repository *PoisonedMessageRepository) Delete(ctx context.Context, messageUUID string) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.messages[messageUUID]; !ok {
+		return entities.ErrPoisonedMessageNotFound
+	}
+	delete(repository.messages, messageUUID)
+
+	return nil
+}
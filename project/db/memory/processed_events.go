@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+type ProcessedEventsRepository struct {
+	lock      sync.Mutex
+	processed map[processedEvent]struct{}
+}
+
+type processedEvent struct {
+	handlerName    string
+	idempotencyKey string
+}
+
+func NewProcessedEventsRepository() *ProcessedEventsRepository {
+	return &ProcessedEventsRepository{processed: map[processedEvent]struct{}{}}
+}
+
+func (repository *ProcessedEventsRepository) IsProcessed(ctx context.Context, handlerName string, idempotencyKey string) (bool, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	_, ok := repository.processed[processedEvent{handlerName, idempotencyKey}]
+	return ok, nil
+}
+
+func (repository *ProcessedEventsRepository) MarkProcessed(ctx context.Context, handlerName string, idempotencyKey string) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	repository.processed[processedEvent{handlerName, idempotencyKey}] = struct{}{}
+	return nil
+}
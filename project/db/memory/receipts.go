@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"tickets/entities"
+)
+
+type ReceiptRepository struct {
+	lock     sync.Mutex
+	receipts map[string]entities.Receipt
+}
+
+func NewReceiptRepository() *ReceiptRepository {
+	return &ReceiptRepository{receipts: map[string]entities.Receipt{}}
+}
+
+func (repository *ReceiptRepository) Save(ctx context.Context, receipt entities.Receipt) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.receipts[receipt.TicketID]; !ok {
+		repository.receipts[receipt.TicketID] = receipt
+	}
+
+	return nil
+}
+
+func (repository *ReceiptRepository) ByTicketID(ctx context.Context, ticketID string) (entities.Receipt, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	receipt, ok := repository.receipts[ticketID]
+	if !ok {
+		return entities.Receipt{}, entities.ErrReceiptNotFound
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestTicketRepository(t *testing.T) {
+	dbtest.TestTicketRepository(t, NewTicketRepository())
+}
+
+func TestShowRepository(t *testing.T) {
+	dbtest.TestShowRepository(t, NewShowRepository())
+}
+
+func TestBookingRepository(t *testing.T) {
+	shows := NewShowRepository()
+	dbtest.TestBookingRepository(t, NewBookingRepository(shows, &eventBusMock{}), shows)
+}
+
+func TestOpsBookingsRepository(t *testing.T) {
+	dbtest.TestOpsBookingsRepository(t, NewOpsBookingsRepository())
+}
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	dbtest.TestIdempotencyKeyRepository(t, NewIdempotencyKeyRepository())
+}
+
+func TestProcessedEventsRepository(t *testing.T) {
+	dbtest.TestProcessedEventsRepository(t, NewProcessedEventsRepository())
+}
+
+func TestReceiptRepository(t *testing.T) {
+	dbtest.TestReceiptRepository(t, NewReceiptRepository())
+}
+
+func TestEventRepository(t *testing.T) {
+	dbtest.TestEventRepository(t, NewEventRepository())
+}
+
+func TestPoisonedMessageRepository(t *testing.T) {
+	dbtest.TestPoisonedMessageRepository(t, NewPoisonedMessageRepository())
+}
+
+func TestScheduledMessageRepository(t *testing.T) {
+	dbtest.TestScheduledMessageRepository(t, NewScheduledMessageRepository())
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+)
+
+type ScheduledMessageRepository struct {
+	lock     sync.Mutex
+	messages map[uuid.UUID]entities.ScheduledMessage
+}
+
+func NewScheduledMessageRepository() *ScheduledMessageRepository {
+	return &ScheduledMessageRepository{messages: map[uuid.UUID]entities.ScheduledMessage{}}
+}
+
+func (repository *ScheduledMessageRepository) Schedule(ctx context.Context, msg entities.ScheduledMessage) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.messages[msg.ID]; !ok {
+		repository.messages[msg.ID] = msg
+	}
+
+	return nil
+}
+
+// DeliverDue passes up to limit messages which are due to deliver, and removes the delivered ones.
+// When deliver fails, none of the messages are removed, and they are delivered again later.
+func (repository *ScheduledMessageRepository) DeliverDue(
+	ctx context.Context,
+	limit int,
+	deliver func(msg entities.ScheduledMessage) error,
+) (int, error) {
+	// the lock is held while delivering, so messages aren't delivered twice by concurrent calls
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	now := time.Now()
+
+	var due []entities.ScheduledMessage
+	for _, msg := range repository.messages {
+		if !msg.DeliverAt.After(now) {
+			due = append(due, msg)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].DeliverAt.Before(due[j].DeliverAt)
+	})
+	due = page(due, limit, 0)
+
+	for _, msg := range due {
+		if err := deliver(msg); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, msg := range due {
+		delete(repository.messages, msg.ID)
+	}
+
+	return len(due), nil
+}
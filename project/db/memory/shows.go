@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+)
+
+// ShowRepository also keeps the bookings, because they are needed for the availability of shows.
+type ShowRepository struct {
+	lock     sync.Mutex
+	shows    map[uuid.UUID]entities.Show
+	bookings map[uuid.UUID]entities.Booking
+}
+
+func NewShowRepository() *ShowRepository {
+	return &ShowRepository{
+		shows:    map[uuid.UUID]entities.Show{},
+		bookings: map[uuid.UUID]entities.Booking{},
+	}
+}
+
+func (repository *ShowRepository) Create(ctx context.Context, show entities.Show) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.shows[show.ID]; !ok {
+		repository.shows[show.ID] = show
+	}
+
+	return nil
+}
+
+func (repository *ShowRepository) All(ctx context.Context, filter entities.ShowsFilter) ([]entities.ShowWithAvailability, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	shows := []entities.ShowWithAvailability{}
+	for _, show := range repository.shows {
+		if filter.Venue != "" && show.Venue != filter.Venue {
+			continue
+		}
+		if filter.StartFrom != nil && show.StartTime.Before(*filter.StartFrom) {
+			continue
+		}
+		if filter.StartTo != nil && show.StartTime.After(*filter.StartTo) {
+			continue
+		}
+
+		shows = append(shows, repository.withAvailability(show))
+	}
+
+	sort.Slice(shows, func(i, j int) bool {
+		if !shows[i].StartTime.Equal(shows[j].StartTime) {
+			return shows[i].StartTime.Before(shows[j].StartTime)
+		}
+		return shows[i].ID.String() < shows[j].ID.String()
+	})
+
+	return page(shows, filter.Limit, filter.Offset), nil
+}
+
+func (repository *ShowRepository) ByID(ctx context.Context, id uuid.UUID) (entities.ShowWithAvailability, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	show, ok := repository.shows[id]
+	if !ok {
+		return entities.ShowWithAvailability{}, entities.ErrShowNotFound
+	}
+
+	return repository.withAvailability(show), nil
+}
+
+func (repository *ShowRepository) withAvailability(show entities.Show) entities.ShowWithAvailability {
+	s := entities.ShowWithAvailability{Show: show}
+
+	for _, booking := range repository.bookings {
+		if booking.ShowID == show.ID {
+			s.BookedTickets += booking.NumberOfTickets
+		}
+	}
+
+	s.RemainingTickets = s.NumberOfTickets - s.BookedTickets
+	if s.RemainingTickets < 0 {
+		s.RemainingTickets = 0
+	}
+
+	return s
+}
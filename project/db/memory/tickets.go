@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"tickets/entities"
+)
+
+type TicketRepository struct {
+	lock    sync.Mutex
+	tickets map[string]entities.Ticket
+	// fileNames are set when tickets are printed
+	fileNames map[string]string
+}
+
+func NewTicketRepository() *TicketRepository {
+	return &TicketRepository{
+		tickets:   map[string]entities.Ticket{},
+		fileNames: map[string]string{},
+	}
+}
+
+func (repository *TicketRepository) Save(ctx context.Context, ticket *entities.Ticket) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.tickets[ticket.ID]; ok {
+		return nil
+	}
+
+	repository.tickets[ticket.ID] = entities.Ticket{
+		ID:            ticket.ID,
+		Status:        entities.TicketStatusConfirmed,
+		Price:         ticket.Price,
+		CustomerEmail: ticket.CustomerEmail,
+	}
+
+	return nil
+}
+
+// Cancel marks the ticket as canceled. If the cancellation arrives before the confirmation,
+// the ticket is stored as canceled right away, and the late confirmation won't overwrite it.
+func (repository *TicketRepository) Cancel(ctx context.Context, ticket *entities.Ticket) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.tickets[ticket.ID]; !ok {
+		repository.tickets[ticket.ID] = entities.Ticket{
+			ID:            ticket.ID,
+			Status:        entities.TicketStatusCanceled,
+			Price:         ticket.Price,
+			CustomerEmail: ticket.CustomerEmail,
+		}
+	}
+
+	return repository.updateStatus(ticket.ID, entities.TicketStatusCanceled)
+}
+
+func (repository *TicketRepository) UpdateStatus(ctx context.Context, ticketID string, status entities.TicketStatus) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	return repository.updateStatus(ticketID, status)
+}
+
+func (repository *TicketRepository) updateStatus(ticketID string, status entities.TicketStatus) error {
+	ticket, ok := repository.tickets[ticketID]
+	if !ok {
+		return entities.ErrTicketNotFound
+	}
+
+	if !ticket.Status.CanTransitionTo(status) {
+		return fmt.Errorf(
+			"%w: ticket %s from %s to %s",
+			entities.ErrInvalidTicketStatusTransition,
+			ticketID,
+			ticket.Status,
+			status,
+		)
+	}
+
+	ticket.Status = status
+	repository.tickets[ticketID] = ticket
+
+	return nil
+}
+
+func (repository *TicketRepository) CheckIn(ctx context.Context, ticketID string) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket, ok := repository.tickets[ticketID]
+	if !ok {
+		return entities.ErrTicketNotFound
+	}
+
+	if err := ticket.CanCheckIn(); err != nil {
+		return err
+	}
+
+	ticket.CheckedIn = true
+	repository.tickets[ticketID] = ticket
+
+	return nil
+}
+
+func (repository *TicketRepository) MarkPrinted(ctx context.Context, ticketID string, fileName string) error {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket, ok := repository.tickets[ticketID]
+	if !ok {
+		return entities.ErrTicketNotFound
+	}
+
+	ticket.Printed = true
+	repository.tickets[ticketID] = ticket
+	repository.fileNames[ticketID] = fileName
+
+	return nil
+}
+
+func (repository *TicketRepository) ByID(ctx context.Context, ticketID string) (entities.Ticket, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	ticket, ok := repository.tickets[ticketID]
+	if !ok {
+		return entities.Ticket{}, entities.ErrTicketNotFound
+	}
+
+	return ticket, nil
+}
+
+func (repository *TicketRepository) FileName(ctx context.Context, ticketID string) (string, error) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.tickets[ticketID]; !ok {
+		return "", entities.ErrTicketNotFound
+	}
+
+	fileName, ok := repository.fileNames[ticketID]
+	if !ok {
+		return "", entities.ErrTicketNotPrinted
+	}
+
+	return fileName, nil
+}
+
+func (repository *TicketRepository) All(ctx context.Context, filter entities.TicketsFilter) ([]entities.Ticket, error) {
+	if filter.Status == "" {
+		filter.Status = entities.TicketStatusConfirmed
+	}
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var tickets []entities.Ticket
+	for _, ticket := range repository.tickets {
+		if ticket.Status == filter.Status {
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ID < tickets[j].ID
+	})
+
+	return tickets, nil
+}
@@ -0,0 +1,298 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OpsBookingsRepository stores the operations read model.
+//
+// Every event only fills in its own columns, and already set timestamps are never overwritten,
+// so handling the same event twice, or handling events out of order, gives the same result.
+type OpsBookingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOpsBookingsRepository(db *pgxpool.Pool) *OpsBookingsRepository {
+	if db == nil {
+		panic("db passed to 'NewOpsBookingsRepository()' is nil!")
+	}
+	return &OpsBookingsRepository{db: db}
+}
+
+func (repository *OpsBookingsRepository) OnBookingMade(ctx context.Context, event *entities.BookingMade) error {
+	q := `INSERT INTO ops_bookings (
+		 booking_id,
+		 show_id,
+		 booked_at,
+		 customer_email,
+		 number_of_tickets
+		 ) VALUES ($1, $2, $3, $4, $5)
+		   ON CONFLICT (booking_id) DO UPDATE SET
+		     show_id = EXCLUDED.show_id,
+		     booked_at = EXCLUDED.booked_at,
+		     customer_email = EXCLUDED.customer_email,
+		     number_of_tickets = EXCLUDED.number_of_tickets;
+	`
+
+	_, err := repository.db.Exec(
+		ctx,
+		q,
+		event.BookingID,
+		event.ShowId,
+		event.Header.PublishedAt,
+		event.CustomerEmail,
+		event.NumberOfTickets,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving ops booking: %w", err)
+	}
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) OnTicketBookingConfirmed(ctx context.Context, event *entities.TicketBookingConfirmed) error {
+	bookingID, err := uuid.Parse(event.BookingID)
+	if err != nil {
+		return fmt.Errorf("invalid booking id %q: %w", event.BookingID, err)
+	}
+
+	return pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		// the booking may not be projected yet, its details will be filled in by BookingMade
+		_, err := tx.Exec(
+			ctx,
+			"INSERT INTO ops_bookings (booking_id) VALUES ($1) ON CONFLICT DO NOTHING",
+			bookingID,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving ops booking: %w", err)
+		}
+
+		q := `INSERT INTO ops_booking_tickets (
+			 ticket_id,
+			 booking_id,
+			 price_amount,
+			 price_currency,
+			 customer_email,
+			 confirmed_at
+			 ) VALUES ($1, $2, $3, $4, $5, $6)
+			   ON CONFLICT (ticket_id) DO UPDATE SET
+			     booking_id = EXCLUDED.booking_id,
+			     price_amount = EXCLUDED.price_amount,
+			     price_currency = EXCLUDED.price_currency,
+			     customer_email = EXCLUDED.customer_email,
+			     confirmed_at = COALESCE(ops_booking_tickets.confirmed_at, EXCLUDED.confirmed_at);
+		`
+
+		_, err = tx.Exec(
+			ctx,
+			q,
+			event.TicketID,
+			bookingID,
+			event.Price.Amount,
+			event.Price.Currency,
+			event.CustomerEmail,
+			event.Header.PublishedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving ops ticket: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (repository *OpsBookingsRepository) OnTicketBookingCanceled(ctx context.Context, event *entities.TicketBookingCanceled) error {
+	return repository.setTicketTimestamp(ctx, event.TicketID, "canceled_at", event.Header.PublishedAt)
+}
+
+func (repository *OpsBookingsRepository) OnTicketRefunded(ctx context.Context, event *entities.TicketRefunded) error {
+	return repository.setTicketTimestamp(ctx, event.TicketID, "refunded_at", event.Header.PublishedAt)
+}
+
+func (repository *OpsBookingsRepository) OnTicketPrinted(ctx context.Context, event *entities.TicketPrinted) error {
+	q := `INSERT INTO ops_booking_tickets (
+		 ticket_id,
+		 printed_at,
+		 printed_file_name
+		 ) VALUES ($1, $2, $3)
+		   ON CONFLICT (ticket_id) DO UPDATE SET
+		     printed_at = COALESCE(ops_booking_tickets.printed_at, EXCLUDED.printed_at),
+		     printed_file_name = EXCLUDED.printed_file_name;
+	`
+
+	_, err := repository.db.Exec(ctx, q, event.TicketID, event.Header.PublishedAt, event.FileName)
+	if err != nil {
+		return fmt.Errorf("error saving printed ops ticket: %w", err)
+	}
+
+	return nil
+}
+
+// setTicketTimestamp is used by events which don't know the ticket's booking.
+// When they arrive first, the ticket row is created without it and linked when the ticket is confirmed.
+func (repository *OpsBookingsRepository) setTicketTimestamp(ctx context.Context, ticketID string, column string, at time.Time) error {
+	q := fmt.Sprintf(`INSERT INTO ops_booking_tickets (
+		 ticket_id,
+		 %[1]s
+		 ) VALUES ($1, $2)
+		   ON CONFLICT (ticket_id) DO UPDATE SET
+		     %[1]s = COALESCE(ops_booking_tickets.%[1]s, EXCLUDED.%[1]s);
+	`, column)
+
+	_, err := repository.db.Exec(ctx, q, ticketID, at)
+	if err != nil {
+		return fmt.Errorf("error setting %s of ops ticket: %w", column, err)
+	}
+
+	return nil
+}
+
+func (repository *OpsBookingsRepository) All(ctx context.Context, filter entities.OpsBookingsFilter) ([]entities.OpsBooking, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Date != nil {
+		args = append(args, filter.Date.Format(time.DateOnly))
+		conditions = append(conditions, fmt.Sprintf("booked_at::date = $%d::date", len(args)))
+	}
+	if filter.ShowID != nil {
+		args = append(args, *filter.ShowID)
+		conditions = append(conditions, fmt.Sprintf("show_id = $%d", len(args)))
+	}
+
+	q := `SELECT booking_id, show_id, booked_at, customer_email, number_of_tickets FROM ops_bookings`
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	q += " ORDER BY booked_at DESC NULLS LAST, booking_id"
+
+	return repository.query(ctx, q, args...)
+}
+
+func (repository *OpsBookingsRepository) ByID(ctx context.Context, bookingID uuid.UUID) (entities.OpsBooking, error) {
+	bookings, err := repository.query(
+		ctx,
+		`SELECT booking_id, show_id, booked_at, customer_email, number_of_tickets FROM ops_bookings WHERE booking_id = $1`,
+		bookingID,
+	)
+	if err != nil {
+		return entities.OpsBooking{}, err
+	}
+
+	if len(bookings) == 0 {
+		return entities.OpsBooking{}, entities.ErrOpsBookingNotFound
+	}
+
+	return bookings[0], nil
+}
+
+func (repository *OpsBookingsRepository) query(ctx context.Context, q string, args ...any) ([]entities.OpsBooking, error) {
+	rows, err := repository.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching ops bookings: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []entities.OpsBooking{}
+	bookingIDs := []uuid.UUID{}
+	for rows.Next() {
+		booking := entities.OpsBooking{Tickets: []entities.OpsTicket{}}
+
+		err := rows.Scan(
+			&booking.BookingID,
+			&booking.ShowID,
+			&booking.BookedAt,
+			&booking.CustomerEmail,
+			&booking.NumberOfTickets,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning ops booking row: %w", err)
+		}
+
+		bookings = append(bookings, booking)
+		bookingIDs = append(bookingIDs, booking.BookingID)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error iterating over ops booking rows: %w", rows.Err())
+	}
+
+	if len(bookings) == 0 {
+		return bookings, nil
+	}
+
+	tickets, err := repository.ticketsByBookingIDs(ctx, bookingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bookings {
+		bookings[i].Tickets = append(bookings[i].Tickets, tickets[bookings[i].BookingID]...)
+	}
+
+	return bookings, nil
+}
+
+func (repository *OpsBookingsRepository) ticketsByBookingIDs(ctx context.Context, bookingIDs []uuid.UUID) (map[uuid.UUID][]entities.OpsTicket, error) {
+	q := `SELECT
+		 ticket_id,
+		 booking_id,
+		 COALESCE(price_amount, 0),
+		 COALESCE(price_currency, ''),
+		 COALESCE(customer_email, ''),
+		 confirmed_at,
+		 canceled_at,
+		 refunded_at,
+		 printed_at,
+		 COALESCE(printed_file_name, '')
+		FROM ops_booking_tickets
+		WHERE booking_id = ANY($1)
+		ORDER BY ticket_id`
+
+	rows, err := repository.db.Query(ctx, q, bookingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching ops tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := map[uuid.UUID][]entities.OpsTicket{}
+	for rows.Next() {
+		var ticket entities.OpsTicket
+		var bookingID uuid.UUID
+
+		err := rows.Scan(
+			&ticket.TicketID,
+			&bookingID,
+			&ticket.Price.Amount,
+			&ticket.Price.Currency,
+			&ticket.CustomerEmail,
+			&ticket.ConfirmedAt,
+			&ticket.CanceledAt,
+			&ticket.RefundedAt,
+			&ticket.PrintedAt,
+			&ticket.PrintedFileName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning ops ticket row: %w", err)
+		}
+
+		ticket.Status = ticket.CurrentStatus()
+
+		tickets[bookingID] = append(tickets[bookingID], ticket)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error iterating over ops ticket rows: %w", rows.Err())
+	}
+
+	return tickets, nil
+}
@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestOpsBookingsRepository(t *testing.T) {
+	dbtest.TestOpsBookingsRepository(t, NewOpsBookingsRepository(getDb()))
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"tickets/entities"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PoisonedMessageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPoisonedMessageRepository(db *pgxpool.Pool) *PoisonedMessageRepository {
+	if db == nil {
+		panic("db passed to 'NewPoisonedMessageRepository()' is nil!")
+	}
+	return &PoisonedMessageRepository{db: db}
+}
+
+func (repository *PoisonedMessageRepository) Save(ctx context.Context, msg entities.PoisonedMessage) error {
+	q := `INSERT INTO poisoned_messages (
+		 message_uuid,
+		 topic,
+		 handler_name,
+		 reason,
+		 payload,
+		 metadata,
+		 poisoned_at
+		 ) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		   ON CONFLICT (message_uuid) DO UPDATE SET
+		     topic = EXCLUDED.topic,
+		     handler_name = EXCLUDED.handler_name,
+		     reason = EXCLUDED.reason,
+		     metadata = EXCLUDED.metadata,
+		     poisoned_at = EXCLUDED.poisoned_at;
+	`
+
+	_, err := repository.db.Exec(
+		ctx,
+		q,
+		msg.MessageUUID,
+		msg.Topic,
+		msg.HandlerName,
+		msg.Reason,
+		[]byte(msg.Payload),
+		msg.Metadata,
+		msg.PoisonedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving poisoned message: %w", err)
+	}
+
+	return nil
+}
+
+func (repository *PoisonedMessageRepository) All(ctx context.Context, filter entities.PoisonedMessagesFilter) ([]entities.PoisonedMessage, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Topic != "" {
+		args = append(args, filter.Topic)
+		conditions = append(conditions, fmt.Sprintf("topic = $%d", len(args)))
+	}
+	if filter.HandlerName != "" {
+		args = append(args, filter.HandlerName)
+		conditions = append(conditions, fmt.Sprintf("handler_name = $%d", len(args)))
+	}
+
+	q := `SELECT message_uuid, topic, handler_name, reason, payload, metadata, poisoned_at FROM poisoned_messages`
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	q += fmt.Sprintf(" ORDER BY poisoned_at, message_uuid LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := repository.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching poisoned messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []entities.PoisonedMessage{}
+	for rows.Next() {
+		msg, err := scanPoisonedMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error iterating over poisoned message rows: %w", rows.Err())
+	}
+
+	return messages, nil
+}
+
+func (repository *PoisonedMessageRepository) ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error) {
+	row := repository.db.QueryRow(
+		ctx,
+		`SELECT message_uuid, topic, handler_name, reason, payload, metadata, poisoned_at
+		FROM poisoned_messages
+		WHERE message_uuid = $1`,
+		messageUUID,
+	)
+
+	msg, err := scanPoisonedMessage(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.PoisonedMessage{}, entities.ErrPoisonedMessageNotFound
+	}
+	if err != nil {
+		return entities.PoisonedMessage{}, err
+	}
+
+	return msg, nil
+}
+
+func (repository *PoisonedMessageRepository) Delete(ctx context.Context, messageUUID string) error {
+	tag, err := repository.db.Exec(ctx, "DELETE FROM poisoned_messages WHERE message_uuid = $1", messageUUID)
+	if err != nil {
+		return fmt.Errorf("error deleting poisoned message: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entities.ErrPoisonedMessageNotFound
+	}
+
+	return nil
+}
+
+func scanPoisonedMessage(row pgx.Row) (entities.PoisonedMessage, error) {
+	var msg entities.PoisonedMessage
+	var payload []byte
+
+	err := row.Scan(
+		&msg.MessageUUID,
+		&msg.Topic,
+		&msg.HandlerName,
+		&msg.Reason,
+		&payload,
+		&msg.Metadata,
+		&msg.PoisonedAt,
+	)
+	if err != nil {
+		return entities.PoisonedMessage{}, fmt.Errorf("error scanning poisoned message row: %w", err)
+	}
+	msg.Payload = string(payload)
+
+	return msg, nil
+}
@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestPoisonedMessageRepository(t *testing.T) {
+	dbtest.TestPoisonedMessageRepository(t, NewPoisonedMessageRepository(getDb()))
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProcessedEventsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProcessedEventsRepository(db *pgxpool.Pool) *ProcessedEventsRepository {
+	if db == nil {
+		panic("db passed to 'NewProcessedEventsRepository()' is nil!")
+	}
+	return &ProcessedEventsRepository{db: db}
+}
+
+func (repository *ProcessedEventsRepository) IsProcessed(ctx context.Context, handlerName string, idempotencyKey string) (bool, error) {
+	var processed bool
+
+	err := repository.db.QueryRow(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM processed_events WHERE handler_name = $1 AND idempotency_key = $2)",
+		handlerName,
+		idempotencyKey,
+	).Scan(&processed)
+	if err != nil {
+		return false, fmt.Errorf("error checking if event was processed: %w", err)
+	}
+
+	return processed, nil
+}
+
+func (repository *ProcessedEventsRepository) MarkProcessed(ctx context.Context, handlerName string, idempotencyKey string) error {
+	_, err := repository.db.Exec(
+		ctx,
+		"INSERT INTO processed_events (handler_name, idempotency_key) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		handlerName,
+		idempotencyKey,
+	)
+	if err != nil {
+		return fmt.Errorf("error marking event as processed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestProcessedEventsRepository(t *testing.T) {
+	dbtest.TestProcessedEventsRepository(t, NewProcessedEventsRepository(getDb()))
+}
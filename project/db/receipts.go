@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReceiptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReceiptRepository(db *pgxpool.Pool) *ReceiptRepository {
+	if db == nil {
+		panic("db passed to 'NewReceiptRepository()' is nil!")
+	}
+	return &ReceiptRepository{db: db}
+}
+
+func (repository *ReceiptRepository) Save(ctx context.Context, receipt entities.Receipt) error {
+	q := `INSERT INTO receipts (
+		 ticket_id,
+		 receipt_number,
+		 issued_at
+		 ) VALUES ($1, $2, $3)
+		   ON CONFLICT DO NOTHING;
+	`
+
+	_, err := repository.db.Exec(ctx, q, receipt.TicketID, receipt.ReceiptNumber, receipt.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("error saving receipt: %w", err)
+	}
+
+	return nil
+}
+
+func (repository *ReceiptRepository) ByTicketID(ctx context.Context, ticketID string) (entities.Receipt, error) {
+	receipt := entities.Receipt{TicketID: ticketID}
+
+	err := repository.db.QueryRow(
+		ctx,
+		"SELECT receipt_number, issued_at FROM receipts WHERE ticket_id = $1",
+		ticketID,
+	).Scan(&receipt.ReceiptNumber, &receipt.IssuedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Receipt{}, entities.ErrReceiptNotFound
+	}
+	if err != nil {
+		return entities.Receipt{}, fmt.Errorf("error fetching receipt: %w", err)
+	}
+
+	return receipt, nil
+}
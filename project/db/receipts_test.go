@@ -0,0 +1,11 @@
+package db
+
+import (
+	"testing"
+
+	"tickets/db/dbtest"
+)
+
+func TestReceiptRepository(t *testing.T) {
+	dbtest.TestReceiptRepository(t, NewReceiptRepository(getDb()))
+}
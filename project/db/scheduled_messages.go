@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScheduledMessageRepository keeps messages of one namespace, so environments sharing Postgres
+// don't deliver each other's messages.
+type ScheduledMessageRepository struct {
+	db        *pgxpool.Pool
+	namespace string
+}
+
+func NewScheduledMessageRepository(db *pgxpool.Pool, namespace string) *ScheduledMessageRepository {
+	if db == nil {
+		panic("db passed to 'NewScheduledMessageRepository()' is nil!")
+	}
+	return &ScheduledMessageRepository{db: db, namespace: namespace}
+}
+
+func (repository *ScheduledMessageRepository) Schedule(ctx context.Context, msg entities.ScheduledMessage) error {
+	q := `INSERT INTO scheduled_messages (
+		 id,
+		 namespace,
+		 message_uuid,
+		 topic,
+		 payload,
+		 metadata,
+		 deliver_at
+		 ) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		   ON CONFLICT DO NOTHING;
+	`
+
+	_, err := repository.db.Exec(
+		ctx,
+		q,
+		msg.ID,
+		repository.namespace,
+		msg.MessageUUID,
+		msg.Topic,
+		msg.Payload,
+		msg.Metadata,
+		msg.DeliverAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error scheduling message: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverDue passes up to limit messages which are due to deliver, and removes the delivered ones.
+// Messages are locked while they are delivered, so many instances can deliver at the same time.
+// When deliver fails, none of the messages are removed, and they are delivered again later.
+func (repository *ScheduledMessageRepository) DeliverDue(
+	ctx context.Context,
+	limit int,
+	deliver func(msg entities.ScheduledMessage) error,
+) (int, error) {
+	delivered := 0
+
+	err := pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(
+			ctx,
+			`SELECT id, message_uuid, topic, payload, metadata, deliver_at
+			FROM scheduled_messages
+			WHERE namespace = $1 AND deliver_at <= $2
+			ORDER BY deliver_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED`,
+			repository.namespace,
+			time.Now().UTC(),
+			limit,
+		)
+		if err != nil {
+			return fmt.Errorf("error fetching scheduled messages: %w", err)
+		}
+
+		var messages []entities.ScheduledMessage
+		for rows.Next() {
+			var msg entities.ScheduledMessage
+
+			err := rows.Scan(&msg.ID, &msg.MessageUUID, &msg.Topic, &msg.Payload, &msg.Metadata, &msg.DeliverAt)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning scheduled message row: %w", err)
+			}
+
+			messages = append(messages, msg)
+		}
+		rows.Close()
+
+		if rows.Err() != nil {
+			return fmt.Errorf("error iterating over scheduled message rows: %w", rows.Err())
+		}
+
+		for _, msg := range messages {
+			if err := deliver(msg); err != nil {
+				return err
+			}
+
+			_, err := tx.Exec(ctx, "DELETE FROM scheduled_messages WHERE id = $1", msg.ID)
+			if err != nil {
+				return fmt.Errorf("error removing delivered message: %w", err)
+			}
+		}
+
+		delivered = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return delivered, nil
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tickets/db/dbtest"
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessageRepository_namespaces(t *testing.T) {
+	ctx := context.Background()
+	staging := NewScheduledMessageRepository(getDb(), "staging-"+uuid.NewString())
+	loadTests := NewScheduledMessageRepository(getDb(), "load-tests-"+uuid.NewString())
+
+	msg := entities.ScheduledMessage{
+		ID:          uuid.New(),
+		MessageUUID: uuid.NewString(),
+		Topic:       "TicketBookingConfirmed",
+		Payload:     []byte(`{}`),
+		Metadata:    map[string]string{"retry_attempt": "1"},
+		DeliverAt:   time.Now().Add(-time.Second),
+	}
+	require.NoError(t, staging.Schedule(ctx, msg))
+
+	var delivered []entities.ScheduledMessage
+	deliver := func(msg entities.ScheduledMessage) error {
+		delivered = append(delivered, msg)
+		return nil
+	}
+
+	count, err := loadTests.DeliverDue(ctx, 10, deliver)
+	require.NoError(t, err)
+	assert.Zero(t, count, "messages of another namespace should not be delivered")
+
+	count, err = staging.DeliverDue(ctx, 10, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, delivered, 1)
+	assert.Equal(t, msg.MessageUUID, delivered[0].MessageUUID)
+}
+
+func TestScheduledMessageRepository(t *testing.T) {
+	dbtest.TestScheduledMessageRepository(t, NewScheduledMessageRepository(getDb(), "test-"+uuid.NewString()))
+}
@@ -13,24 +13,127 @@ func CreateDatabaseSchema(db *pgxpool.Pool) error {
 			ticket_id UUID PRIMARY KEY,
 			price_amount NUMERIC(10, 2) NOT NULL,
 			price_currency CHAR(3) NOT NULL,
-			customer_email VARCHAR(255) NOT NULL
+			customer_email VARCHAR(255) NOT NULL,
+			status VARCHAR(32) NOT NULL DEFAULT 'confirmed',
+			printed BOOLEAN NOT NULL DEFAULT false,
+			checked_in BOOLEAN NOT NULL DEFAULT false,
+			file_name VARCHAR(255)
 		);
 
+		ALTER TABLE tickets ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'confirmed';
+		ALTER TABLE tickets ADD COLUMN IF NOT EXISTS printed BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE tickets ADD COLUMN IF NOT EXISTS checked_in BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE tickets ADD COLUMN IF NOT EXISTS file_name VARCHAR(255);
+		CREATE INDEX IF NOT EXISTS tickets_status_idx ON tickets (status);
+
 		CREATE TABLE IF NOT EXISTS shows (
 		    id UUID PRIMARY KEY,
 		    dead_nation_id VARCHAR(255) NOT NULL,
 		    number_of_tickets INTEGER NOT NULL,
 		    start_time TIMESTAMP NOT NULL,
 		    title VARCHAR(255) NOT NULL,
-		    venue VARCHAR(255) NOT NULL
+		    venue VARCHAR(255) NOT NULL,
+		    ticket_price_amount NUMERIC(10, 2) NOT NULL DEFAULT 0,
+		    ticket_price_currency CHAR(3) NOT NULL DEFAULT 'USD'
 		);
 
+		ALTER TABLE shows ADD COLUMN IF NOT EXISTS ticket_price_amount NUMERIC(10, 2) NOT NULL DEFAULT 0;
+		ALTER TABLE shows ADD COLUMN IF NOT EXISTS ticket_price_currency CHAR(3) NOT NULL DEFAULT 'USD';
+
 		CREATE TABLE IF NOT EXISTS bookings (
 		    id UUID PRIMARY KEY,
 		    show_id UUID NOT NULL,
 		    number_of_tickets INTEGER NOT NULL,
 		    customer_email VARCHAR(255) NOT NULL
-		)
+		);
+
+		CREATE TABLE IF NOT EXISTS receipts (
+		    ticket_id UUID PRIMARY KEY,
+		    receipt_number VARCHAR(255) NOT NULL,
+		    issued_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS ops_bookings (
+		    booking_id UUID PRIMARY KEY,
+		    show_id UUID,
+		    booked_at TIMESTAMP,
+		    customer_email VARCHAR(255) NOT NULL DEFAULT '',
+		    number_of_tickets INTEGER NOT NULL DEFAULT 0
+		);
+
+		CREATE INDEX IF NOT EXISTS ops_bookings_booked_at_idx ON ops_bookings (booked_at);
+		CREATE INDEX IF NOT EXISTS ops_bookings_show_id_idx ON ops_bookings (show_id);
+
+		CREATE TABLE IF NOT EXISTS ops_booking_tickets (
+		    ticket_id UUID PRIMARY KEY,
+		    booking_id UUID,
+		    price_amount NUMERIC(10, 2),
+		    price_currency CHAR(3),
+		    customer_email VARCHAR(255),
+		    confirmed_at TIMESTAMP,
+		    canceled_at TIMESTAMP,
+		    refunded_at TIMESTAMP,
+		    printed_at TIMESTAMP,
+		    printed_file_name VARCHAR(255)
+		);
+
+		CREATE INDEX IF NOT EXISTS ops_booking_tickets_booking_id_idx ON ops_booking_tickets (booking_id);
+
+		CREATE TABLE IF NOT EXISTS events (
+		    event_id UUID PRIMARY KEY,
+		    event_name VARCHAR(255) NOT NULL,
+		    version INTEGER NOT NULL DEFAULT 1,
+		    correlation_id VARCHAR(255) NOT NULL,
+		    published_at TIMESTAMP NOT NULL,
+		    payload JSONB NOT NULL
+		);
+
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+		CREATE INDEX IF NOT EXISTS events_event_name_idx ON events (event_name);
+		CREATE INDEX IF NOT EXISTS events_correlation_id_idx ON events (correlation_id);
+		CREATE INDEX IF NOT EXISTS events_published_at_idx ON events (published_at);
+
+		CREATE TABLE IF NOT EXISTS poisoned_messages (
+		    message_uuid VARCHAR(255) PRIMARY KEY,
+		    topic VARCHAR(255) NOT NULL,
+		    handler_name VARCHAR(255) NOT NULL,
+		    reason TEXT NOT NULL,
+		    payload BYTEA NOT NULL,
+		    metadata JSONB NOT NULL,
+		    poisoned_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS scheduled_messages (
+		    id UUID PRIMARY KEY,
+		    namespace VARCHAR(255) NOT NULL DEFAULT '',
+		    message_uuid VARCHAR(255) NOT NULL,
+		    topic VARCHAR(255) NOT NULL,
+		    payload BYTEA NOT NULL,
+		    metadata JSONB NOT NULL,
+		    deliver_at TIMESTAMP NOT NULL
+		);
+
+		ALTER TABLE scheduled_messages ADD COLUMN IF NOT EXISTS namespace VARCHAR(255) NOT NULL DEFAULT '';
+		DROP INDEX IF EXISTS scheduled_messages_deliver_at_idx;
+		CREATE INDEX IF NOT EXISTS scheduled_messages_namespace_deliver_at_idx ON scheduled_messages (namespace, deliver_at);
+
+		CREATE TABLE IF NOT EXISTS processed_events (
+		    handler_name VARCHAR(255) NOT NULL,
+		    idempotency_key VARCHAR(255) NOT NULL,
+		    processed_at TIMESTAMP NOT NULL DEFAULT now(),
+		    PRIMARY KEY (handler_name, idempotency_key)
+		);
+
+		CREATE TABLE IF NOT EXISTS http_idempotency_keys (
+		    idempotency_key VARCHAR(255) PRIMARY KEY,
+		    request_hash CHAR(64) NOT NULL,
+		    locked_at TIMESTAMP NOT NULL,
+		    response_status_code INTEGER,
+		    response_content_type VARCHAR(255),
+		    response_body BYTEA,
+		    completed_at TIMESTAMP
+		);
 	`)
 	if err != nil {
 		return fmt.Errorf("pgxpool error: error executing create table query: %w", err)
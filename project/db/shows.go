@@ -2,11 +2,29 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
 	"tickets/entities"
 )
 
+const selectShowsWithAvailability = `
+	SELECT
+	    s.id,
+	    s.dead_nation_id,
+	    s.number_of_tickets,
+	    s.start_time,
+	    s.title,
+	    s.venue,
+	    s.ticket_price_amount,
+	    s.ticket_price_currency,
+	    COALESCE(SUM(b.number_of_tickets), 0) AS booked_tickets
+	FROM shows s
+	LEFT JOIN bookings b ON b.show_id = s.id`
+
 type ShowRepository struct {
 	db *pgxpool.Pool
 }
@@ -25,8 +43,10 @@ func (repository *ShowRepository) Create(ctx context.Context, show entities.Show
 		 number_of_tickets, 
 		 start_time, 
 		 title,
-         venue
-		 ) VALUES ($1, $2, $3, $4, $5, $6) 
+         venue,
+		 ticket_price_amount,
+		 ticket_price_currency
+		 ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
 		   ON CONFLICT DO NOTHING;
 	`
 
@@ -39,6 +59,8 @@ func (repository *ShowRepository) Create(ctx context.Context, show entities.Show
 		show.StartTime,
 		show.Title,
 		show.Venue,
+		show.TicketPrice.Amount,
+		show.TicketPrice.Currency,
 	)
 
 	if err != nil {
@@ -47,3 +69,91 @@ func (repository *ShowRepository) Create(ctx context.Context, show entities.Show
 
 	return nil
 }
+
+func (repository *ShowRepository) All(ctx context.Context, filter entities.ShowsFilter) ([]entities.ShowWithAvailability, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Venue != "" {
+		args = append(args, filter.Venue)
+		conditions = append(conditions, fmt.Sprintf("s.venue = $%d", len(args)))
+	}
+	if filter.StartFrom != nil {
+		args = append(args, *filter.StartFrom)
+		conditions = append(conditions, fmt.Sprintf("s.start_time >= $%d", len(args)))
+	}
+	if filter.StartTo != nil {
+		args = append(args, *filter.StartTo)
+		conditions = append(conditions, fmt.Sprintf("s.start_time <= $%d", len(args)))
+	}
+
+	q := selectShowsWithAvailability
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	q += fmt.Sprintf(" GROUP BY s.id ORDER BY s.start_time, s.id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := repository.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching shows: %w", err)
+	}
+	defer rows.Close()
+
+	shows := []entities.ShowWithAvailability{}
+	for rows.Next() {
+		show, err := scanShowWithAvailability(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		shows = append(shows, show)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error iterating over show rows: %w", rows.Err())
+	}
+
+	return shows, nil
+}
+
+func (repository *ShowRepository) ByID(ctx context.Context, id uuid.UUID) (entities.ShowWithAvailability, error) {
+	row := repository.db.QueryRow(ctx, selectShowsWithAvailability+" WHERE s.id = $1 GROUP BY s.id", id)
+
+	show, err := scanShowWithAvailability(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.ShowWithAvailability{}, entities.ErrShowNotFound
+	}
+	if err != nil {
+		return entities.ShowWithAvailability{}, err
+	}
+
+	return show, nil
+}
+
+func scanShowWithAvailability(row pgx.Row) (entities.ShowWithAvailability, error) {
+	var show entities.ShowWithAvailability
+
+	err := row.Scan(
+		&show.ID,
+		&show.DeadNationID,
+		&show.NumberOfTickets,
+		&show.StartTime,
+		&show.Title,
+		&show.Venue,
+		&show.TicketPrice.Amount,
+		&show.TicketPrice.Currency,
+		&show.BookedTickets,
+	)
+	if err != nil {
+		return entities.ShowWithAvailability{}, fmt.Errorf("error scanning show row: %w", err)
+	}
+
+	show.RemainingTickets = show.NumberOfTickets - show.BookedTickets
+	if show.RemainingTickets < 0 {
+		show.RemainingTickets = 0
+	}
+
+	return show, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tickets/db/dbtest"
+	"tickets/entities"
+	"tickets/message/event"
+	"tickets/message/outbox"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowAvailability(t *testing.T) {
+	ctx := context.Background()
+	repository := NewShowRepository(getDb())
+
+	show := entities.Show{
+		ID:              uuid.New(),
+		DeadNationID:    uuid.New(),
+		NumberOfTickets: 10,
+		StartTime:       time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second),
+		Title:           "Availability test",
+		Venue:           "venue-" + uuid.NewString(),
+	}
+	require.NoError(t, repository.Create(ctx, show))
+
+	err := NewBookingRepository(getDb(), getStdDb(), event.NewMarshaler(event.FormatJSON), outbox.Topic).Create(ctx, entities.Booking{
+		ID:              uuid.New(),
+		ShowID:          show.ID,
+		NumberOfTickets: 3,
+		CustomerEmail:   "customer@example.com",
+	})
+	require.NoError(t, err)
+
+	found, err := repository.ByID(ctx, show.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, found.BookedTickets)
+	assert.Equal(t, 7, found.RemainingTickets)
+
+	shows, err := repository.All(ctx, entities.ShowsFilter{Venue: show.Venue, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, shows, 1)
+	assert.Equal(t, show.ID, shows[0].ID)
+	assert.Equal(t, 7, shows[0].RemainingTickets)
+
+	_, err = repository.ByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, entities.ErrShowNotFound)
+}
+
+func TestShowRepository(t *testing.T) {
+	dbtest.TestShowRepository(t, NewShowRepository(getDb()))
+}
@@ -2,10 +2,12 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"tickets/entities"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,15 +21,24 @@ func NewTicketRepository(db *pgxpool.Pool) *TicketRepository {
 
 func (repository *TicketRepository) Save(ctx context.Context, ticket *entities.Ticket) error {
 	q := `INSERT INTO tickets (
-		 ticket_id, 
-		 price_amount, 
-		 price_currency, 
-		 customer_email
-		 ) VALUES ($1, $2, $3, $4) 
+		 ticket_id,
+		 price_amount,
+		 price_currency,
+		 customer_email,
+		 status
+		 ) VALUES ($1, $2, $3, $4, $5)
 		   ON CONFLICT DO NOTHING;
 	`
 
-	_, err := repository.db.Exec(ctx, q, ticket.ID, ticket.Price.Amount, ticket.Price.Currency, ticket.CustomerEmail)
+	_, err := repository.db.Exec(
+		ctx,
+		q,
+		ticket.ID,
+		ticket.Price.Amount,
+		ticket.Price.Currency,
+		ticket.CustomerEmail,
+		entities.TicketStatusConfirmed,
+	)
 	if err != nil {
 		return fmt.Errorf("error saving ticket: %w", err)
 	}
@@ -35,19 +46,181 @@ func (repository *TicketRepository) Save(ctx context.Context, ticket *entities.T
 	return nil
 }
 
-func (repository *TicketRepository) Delete(ctx context.Context, id string) error {
-	_, err := repository.db.Exec(ctx, "DELETE FROM tickets WHERE ticket_id = $1;", id)
+// Cancel marks the ticket as canceled. If the cancellation arrives before the confirmation,
+// the ticket is stored as canceled right away, and the late confirmation won't overwrite it.
+func (repository *TicketRepository) Cancel(ctx context.Context, ticket *entities.Ticket) error {
+	return pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		q := `INSERT INTO tickets (
+			 ticket_id,
+			 price_amount,
+			 price_currency,
+			 customer_email,
+			 status
+			 ) VALUES ($1, $2, $3, $4, $5)
+			   ON CONFLICT DO NOTHING;
+		`
+
+		_, err := tx.Exec(
+			ctx,
+			q,
+			ticket.ID,
+			ticket.Price.Amount,
+			ticket.Price.Currency,
+			ticket.CustomerEmail,
+			entities.TicketStatusCanceled,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving canceled ticket: %w", err)
+		}
+
+		return updateTicketStatus(ctx, tx, ticket.ID, entities.TicketStatusCanceled)
+	})
+}
+
+func (repository *TicketRepository) UpdateStatus(ctx context.Context, ticketID string, status entities.TicketStatus) error {
+	return pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		return updateTicketStatus(ctx, tx, ticketID, status)
+	})
+}
+
+func (repository *TicketRepository) CheckIn(ctx context.Context, ticketID string) error {
+	return pgx.BeginFunc(ctx, repository.db, func(tx pgx.Tx) error {
+		ticket, err := ticketForUpdate(ctx, tx, ticketID)
+		if err != nil {
+			return err
+		}
+
+		if err := ticket.CanCheckIn(); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE tickets SET checked_in = true WHERE ticket_id = $1", ticketID)
+		if err != nil {
+			return fmt.Errorf("error checking in ticket: %w", err)
+		}
 
+		return nil
+	})
+}
+
+func (repository *TicketRepository) MarkPrinted(ctx context.Context, ticketID string, fileName string) error {
+	tag, err := repository.db.Exec(
+		ctx,
+		"UPDATE tickets SET printed = true, file_name = $1 WHERE ticket_id = $2",
+		fileName,
+		ticketID,
+	)
 	if err != nil {
-		return fmt.Errorf("error deleting ticket: %w", err)
+		return fmt.Errorf("error marking ticket as printed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entities.ErrTicketNotFound
 	}
 
 	return nil
 }
 
-func (repository *TicketRepository) All(ctx context.Context) ([]entities.Ticket, error) {
-	q := `SELECT ticket_id, price_amount, price_currency, customer_email FROM tickets;`
-	rows, err := repository.db.Query(ctx, q)
+func (repository *TicketRepository) ByID(ctx context.Context, ticketID string) (entities.Ticket, error) {
+	ticket := entities.Ticket{ID: ticketID}
+
+	err := repository.db.QueryRow(
+		ctx,
+		`SELECT status, price_amount, price_currency, customer_email, printed, checked_in
+		FROM tickets
+		WHERE ticket_id = $1`,
+		ticketID,
+	).Scan(
+		&ticket.Status,
+		&ticket.Price.Amount,
+		&ticket.Price.Currency,
+		&ticket.CustomerEmail,
+		&ticket.Printed,
+		&ticket.CheckedIn,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Ticket{}, entities.ErrTicketNotFound
+	}
+	if err != nil {
+		return entities.Ticket{}, fmt.Errorf("error fetching ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+func (repository *TicketRepository) FileName(ctx context.Context, ticketID string) (string, error) {
+	var fileName *string
+
+	err := repository.db.QueryRow(
+		ctx,
+		"SELECT file_name FROM tickets WHERE ticket_id = $1",
+		ticketID,
+	).Scan(&fileName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", entities.ErrTicketNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching ticket file name: %w", err)
+	}
+
+	if fileName == nil {
+		return "", entities.ErrTicketNotPrinted
+	}
+
+	return *fileName, nil
+}
+
+func updateTicketStatus(ctx context.Context, tx pgx.Tx, ticketID string, status entities.TicketStatus) error {
+	ticket, err := ticketForUpdate(ctx, tx, ticketID)
+	if err != nil {
+		return err
+	}
+
+	if !ticket.Status.CanTransitionTo(status) {
+		return fmt.Errorf(
+			"%w: ticket %s from %s to %s",
+			entities.ErrInvalidTicketStatusTransition,
+			ticketID,
+			ticket.Status,
+			status,
+		)
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE tickets SET status = $1 WHERE ticket_id = $2", status, ticketID)
+	if err != nil {
+		return fmt.Errorf("error updating ticket status: %w", err)
+	}
+
+	return nil
+}
+
+func ticketForUpdate(ctx context.Context, tx pgx.Tx, ticketID string) (entities.Ticket, error) {
+	ticket := entities.Ticket{ID: ticketID}
+
+	err := tx.QueryRow(
+		ctx,
+		"SELECT status, printed, checked_in FROM tickets WHERE ticket_id = $1 FOR UPDATE",
+		ticketID,
+	).Scan(&ticket.Status, &ticket.Printed, &ticket.CheckedIn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Ticket{}, entities.ErrTicketNotFound
+	}
+	if err != nil {
+		return entities.Ticket{}, fmt.Errorf("error locking ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+func (repository *TicketRepository) All(ctx context.Context, filter entities.TicketsFilter) ([]entities.Ticket, error) {
+	if filter.Status == "" {
+		filter.Status = entities.TicketStatusConfirmed
+	}
+
+	q := `SELECT ticket_id, status, price_amount, price_currency, customer_email, printed, checked_in
+		FROM tickets
+		WHERE status = $1;`
+	rows, err := repository.db.Query(ctx, q, filter.Status)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching tickets: %w", err)
 	}
@@ -56,19 +229,20 @@ func (repository *TicketRepository) All(ctx context.Context) ([]entities.Ticket,
 	var tickets []entities.Ticket
 	for rows.Next() {
 		var ticket entities.Ticket
-		var priceAmount float64
-		var priceCurrency string
 
-		err := rows.Scan(&ticket.ID, &priceAmount, &priceCurrency, &ticket.CustomerEmail)
+		err := rows.Scan(
+			&ticket.ID,
+			&ticket.Status,
+			&ticket.Price.Amount,
+			&ticket.Price.Currency,
+			&ticket.CustomerEmail,
+			&ticket.Printed,
+			&ticket.CheckedIn,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning ticket row: %w", err)
 		}
 
-		ticket.Price = entities.Price{
-			Amount:   fmt.Sprintf("%.2f", priceAmount),
-			Currency: priceCurrency,
-		}
-
 		tickets = append(tickets, ticket)
 	}
 
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"testing"
 
+	"tickets/db/dbtest"
 	"tickets/entities"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -38,7 +39,7 @@ func TestForIdempotency(t *testing.T) {
 		ID:     "8e7de3b9-9209-42eb-ab3d-886402ee45d2",
 		Status: "completed",
 		Price: entities.Price{
-			Amount:   "100.25",
+			Amount:   entities.MustNewDecimal("100.25"),
 			Currency: "PHP",
 		},
 		CustomerEmail: "customer@example.com",
@@ -49,10 +50,14 @@ func TestForIdempotency(t *testing.T) {
 	err2 := repository.Save(context.Background(), &ticket)
 	assert.NoError(t, err2)
 
-	all, err3 := repository.All(context.Background())
+	all, err3 := repository.All(context.Background(), entities.TicketsFilter{})
 	fmt.Println(all)
 	assert.NoError(t, err3)
 
 	assert.Equal(t, 1, len(all))
 	assert.Equal(t, ticket.ID, all[0].ID)
 }
+
+func TestTicketRepository(t *testing.T) {
+	dbtest.TestTicketRepository(t, NewTicketRepository(getDb()))
+}
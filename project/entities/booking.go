@@ -1,6 +1,16 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShowNotFound          = errors.New("show not found")
+	ErrNotEnoughTicketsLeft  = errors.New("not enough tickets left for this show")
+	ErrInvalidTicketsBooking = errors.New("number of tickets must be greater than zero")
+)
 
 type Booking struct {
 	ID              uuid.UUID `json:"id"`
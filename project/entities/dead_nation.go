@@ -0,0 +1,11 @@
+package entities
+
+import "github.com/google/uuid"
+
+type DeadNationBooking struct {
+	BookingID         uuid.UUID
+	DeadNationEventID uuid.UUID
+	NumberOfTickets   int
+	CustomerEmail     string
+	IdempotencyKey    string
+}
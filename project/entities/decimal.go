@@ -0,0 +1,191 @@
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+// Decimal is an exact decimal number, stored as an unscaled integer and the number of digits after the point.
+// It keeps the scale it was created with, so "30.00" is formatted back as "30.00".
+//
+// The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+func NewDecimal(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	integerPart, fractionalPart, hasPoint := strings.Cut(digits, ".")
+	if integerPart == "" || (hasPoint && fractionalPart == "") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	for _, r := range integerPart + fractionalPart {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+		}
+	}
+
+	unscaled, ok := new(big.Int).SetString(integerPart+fractionalPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: int32(len(fractionalPart))}, nil
+}
+
+// MustNewDecimal is like NewDecimal, but panics on invalid input. It's meant for constants and tests.
+func MustNewDecimal(value string) Decimal {
+	d, err := NewDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{unscaled: big.NewInt(value)}
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+// rescale returns the unscaled value with the given scale, which can't be lower than the current one.
+func (d Decimal) rescale(scale int32) *big.Int {
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-d.scale)), nil)
+	return factor.Mul(factor, d.value())
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) MulInt(multiplier int64) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), big.NewInt(multiplier)), scale: d.scale}
+}
+
+// Cmp returns -1, 0 or 1 if d is lower than, equal to or greater than other.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) IsZero() bool {
+	return d.value().Sign() == 0
+}
+
+func (d Decimal) IsNegative() bool {
+	return d.value().Sign() < 0
+}
+
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+
+	if d.scale > 0 {
+		if missing := int(d.scale) - len(digits) + 1; missing > 0 {
+			digits = strings.Repeat("0", missing) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if d.IsNegative() {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// MarshalJSON encodes the decimal as a string, so no precision is lost by JSON decoders using floats.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts both strings and plain JSON numbers, null is decoded as zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	value := string(data)
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := NewDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	var value string
+
+	switch src := src.(type) {
+	case string:
+		value = src
+	case []byte:
+		value = string(src)
+	case int64:
+		*d = NewDecimalFromInt(src)
+		return nil
+	default:
+		return fmt.Errorf("%w: can't scan %T", ErrInvalidDecimal, src)
+	}
+
+	parsed, err := NewDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// Protobuf messages of the events are generated from events.proto into the eventspb package.
+//go:generate protoc --proto_path=.. --go_out=.. --go_opt=module=tickets entities/events.proto
+
 type EventHeader struct {
 	ID             string    `json:"id"`
 	PublishedAt    time.Time `json:"published_at"`
@@ -28,6 +31,19 @@ func NewEventHeaderWithIdempotencyKey(key string) EventHeader {
 	}
 }
 
+// DeduplicationKey identifies the event among its redeliveries and republications.
+func (h EventHeader) DeduplicationKey() string {
+	if h.IdempotencyKey != "" {
+		return h.IdempotencyKey
+	}
+
+	return h.ID
+}
+
+type Event interface {
+	GetHeader() EventHeader
+}
+
 type TicketBookingConfirmed struct {
 	Header        EventHeader `json:"header"`
 	TicketID      string      `json:"ticket_id"`
@@ -62,3 +78,35 @@ type TicketPrinted struct {
 	TicketID string `json:"ticket_id"`
 	FileName string `json:"file_name"`
 }
+
+type TicketReceiptIssued struct {
+	Header EventHeader `json:"header"`
+
+	TicketID      string    `json:"ticket_id"`
+	ReceiptNumber string    `json:"receipt_number"`
+	IssuedAt      time.Time `json:"issued_at"`
+}
+
+func (e TicketBookingConfirmed) GetHeader() EventHeader {
+	return e.Header
+}
+
+func (e TicketBookingCanceled) GetHeader() EventHeader {
+	return e.Header
+}
+
+func (e TicketRefunded) GetHeader() EventHeader {
+	return e.Header
+}
+
+func (e BookingMade) GetHeader() EventHeader {
+	return e.Header
+}
+
+func (e TicketPrinted) GetHeader() EventHeader {
+	return e.Header
+}
+
+func (e TicketReceiptIssued) GetHeader() EventHeader {
+	return e.Header
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "errors"
+
+var (
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+)
+
+// IdempotentResponse is the response stored for an idempotency key, replayed for repeated requests.
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
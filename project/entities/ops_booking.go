@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOpsBookingNotFound = errors.New("ops booking not found")
+
+// OpsBooking is a read model for the operations team, built from booking and ticket events.
+// Fields are filled in as events arrive, so some of them may be empty until all events are processed.
+type OpsBooking struct {
+	BookingID       uuid.UUID  `json:"booking_id"`
+	ShowID          *uuid.UUID `json:"show_id"`
+	BookedAt        *time.Time `json:"booked_at"`
+	CustomerEmail   string     `json:"customer_email"`
+	NumberOfTickets int        `json:"number_of_tickets"`
+
+	Tickets []OpsTicket `json:"tickets"`
+}
+
+type OpsTicket struct {
+	TicketID      string       `json:"ticket_id"`
+	Status        TicketStatus `json:"status"`
+	Price         Price        `json:"price"`
+	CustomerEmail string       `json:"customer_email"`
+
+	ConfirmedAt     *time.Time `json:"confirmed_at"`
+	CanceledAt      *time.Time `json:"canceled_at"`
+	RefundedAt      *time.Time `json:"refunded_at"`
+	PrintedAt       *time.Time `json:"printed_at"`
+	PrintedFileName string     `json:"printed_file_name"`
+}
+
+// CurrentStatus derives the ticket status from the events seen so far.
+// It doesn't depend on the order in which the events were processed.
+func (t OpsTicket) CurrentStatus() TicketStatus {
+	switch {
+	case t.RefundedAt != nil:
+		return TicketStatusRefunded
+	case t.CanceledAt != nil:
+		return TicketStatusCanceled
+	case t.ConfirmedAt != nil:
+		return TicketStatusConfirmed
+	default:
+		return ""
+	}
+}
+
+type OpsBookingsFilter struct {
+	Date   *time.Time
+	ShowID *uuid.UUID
+}
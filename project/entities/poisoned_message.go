@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrPoisonedMessageNotFound = errors.New("poisoned message not found")
+
+// PoisonedMessage is a message which failed all retries, kept until it's requeued or deleted.
+type PoisonedMessage struct {
+	MessageUUID string            `json:"message_uuid"`
+	Topic       string            `json:"topic"`
+	HandlerName string            `json:"handler_name"`
+	Reason      string            `json:"reason"`
+	Payload     string            `json:"payload"`
+	Metadata    map[string]string `json:"metadata"`
+	PoisonedAt  time.Time         `json:"poisoned_at"`
+}
+
+type PoisonedMessagesFilter struct {
+	Topic       string
+	HandlerName string
+	Limit       int
+	Offset      int
+}
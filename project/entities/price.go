@@ -1,6 +1,77 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPrice     = errors.New("invalid price")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
 type Price struct {
-	Amount   string `json:"amount"`
-	Currency string `json:"currency"`
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func NewPrice(amount string, currency string) (Price, error) {
+	decimalAmount, err := NewDecimal(amount)
+	if err != nil {
+		return Price{}, fmt.Errorf("%w: %w", ErrInvalidPrice, err)
+	}
+
+	price := Price{Amount: decimalAmount, Currency: currency}
+	if err := price.Validate(); err != nil {
+		return Price{}, err
+	}
+
+	return price, nil
+}
+
+func (p Price) Validate() error {
+	if !IsValidCurrency(p.Currency) {
+		return fmt.Errorf("%w: unknown currency %q", ErrInvalidPrice, p.Currency)
+	}
+	if p.Amount.IsNegative() {
+		return fmt.Errorf("%w: negative amount %s", ErrInvalidPrice, p.Amount)
+	}
+
+	return nil
+}
+
+func (p Price) Add(other Price) (Price, error) {
+	if p.Currency != other.Currency {
+		return Price{}, fmt.Errorf("%w: can't add %s to %s", ErrCurrencyMismatch, other.Currency, p.Currency)
+	}
+
+	return Price{Amount: p.Amount.Add(other.Amount), Currency: p.Currency}, nil
+}
+
+func (p Price) Mul(quantity int) Price {
+	return Price{Amount: p.Amount.MulInt(int64(quantity)), Currency: p.Currency}
+}
+
+func (p Price) String() string {
+	return p.Amount.String() + " " + p.Currency
+}
+
+// PriceTotals sums prices in different currencies, for example total sales of a show.
+type PriceTotals map[string]Decimal
+
+func SumPrices(prices ...Price) PriceTotals {
+	totals := PriceTotals{}
+	for _, price := range prices {
+		totals.Add(price)
+	}
+
+	return totals
+}
+
+func (t PriceTotals) Add(price Price) {
+	t[price.Currency] = t[price.Currency].Add(price.Amount)
+}
+
+func (t PriceTotals) Get(currency string) Price {
+	return Price{Amount: t[currency], Currency: currency}
 }
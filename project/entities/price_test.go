@@ -0,0 +1,102 @@
+package entities_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDecimal(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected string
+		Invalid  bool
+	}{
+		{Input: "30.00", Expected: "30.00"},
+		{Input: "0.05", Expected: "0.05"},
+		{Input: "-0.05", Expected: "-0.05"},
+		{Input: "+12", Expected: "12"},
+		{Input: "1234567890123456789012.123456789", Expected: "1234567890123456789012.123456789"},
+		{Input: "", Invalid: true},
+		{Input: "1.", Invalid: true},
+		{Input: ".5", Invalid: true},
+		{Input: "1e3", Invalid: true},
+		{Input: "--1", Invalid: true},
+		{Input: "1,50", Invalid: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			d, err := entities.NewDecimal(tc.Input)
+			if tc.Invalid {
+				assert.ErrorIs(t, err, entities.ErrInvalidDecimal)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, d.String())
+		})
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := entities.MustNewDecimal("0.1")
+	b := entities.MustNewDecimal("0.20")
+
+	assert.Equal(t, "0.30", a.Add(b).String())
+	assert.Equal(t, "-0.10", a.Sub(b).String())
+	assert.Equal(t, "0.60", b.MulInt(3).String())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.True(t, entities.MustNewDecimal("0.30").Equal(entities.MustNewDecimal("0.3")))
+	assert.Equal(t, "0.1", entities.Decimal{}.Add(a).String())
+}
+
+func TestPrice_JSON(t *testing.T) {
+	var price entities.Price
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"50.30","currency":"EUR"}`), &price))
+	assert.Equal(t, "50.30", price.Amount.String())
+
+	marshaled, err := json.Marshal(price)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"50.30","currency":"EUR"}`, string(marshaled))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":12.5,"currency":"USD"}`), &price))
+	assert.Equal(t, "12.5", price.Amount.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"abc","currency":"USD"}`), &price))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":null,"currency":"USD"}`), &price))
+	assert.True(t, price.Amount.IsZero())
+}
+
+func TestPrice_Validate(t *testing.T) {
+	_, err := entities.NewPrice("10.00", "EUR")
+	assert.NoError(t, err)
+
+	_, err = entities.NewPrice("10.00", "EURO")
+	assert.ErrorIs(t, err, entities.ErrInvalidPrice)
+
+	_, err = entities.NewPrice("-1.00", "EUR")
+	assert.ErrorIs(t, err, entities.ErrInvalidPrice)
+
+	_, err = entities.NewPrice("ten", "EUR")
+	assert.ErrorIs(t, err, entities.ErrInvalidPrice)
+}
+
+func TestSumPrices(t *testing.T) {
+	totals := entities.SumPrices(
+		entities.Price{Amount: entities.MustNewDecimal("25.00"), Currency: "EUR"},
+		entities.Price{Amount: entities.MustNewDecimal("25.00"), Currency: "EUR"}.Mul(2),
+		entities.Price{Amount: entities.MustNewDecimal("10.10"), Currency: "USD"},
+	)
+
+	assert.Equal(t, "75.00 EUR", totals.Get("EUR").String())
+	assert.Equal(t, "10.10 USD", totals.Get("USD").String())
+
+	_, err := entities.Price{Currency: "EUR"}.Add(entities.Price{Currency: "USD"})
+	assert.ErrorIs(t, err, entities.ErrCurrencyMismatch)
+}
@@ -1,9 +1,12 @@
 package entities
 
 import (
+	"errors"
 	"time"
 )
 
+var ErrReceiptNotFound = errors.New("receipt not found")
+
 type IssueReceiptRequest struct {
 	IdempotencyKey string
 	TicketID       string
@@ -14,3 +17,15 @@ type IssueReceiptResponse struct {
 	ReceiptNumber string    `json:"number"`
 	IssuedAt      time.Time `json:"issued_at"`
 }
+
+type VoidReceipt struct {
+	TicketID       string
+	Reason         string
+	IdempotencyKey string
+}
+
+type Receipt struct {
+	TicketID      string    `json:"ticket_id"`
+	ReceiptNumber string    `json:"number"`
+	IssuedAt      time.Time `json:"issued_at"`
+}
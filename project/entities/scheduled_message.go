@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledMessage waits in the database until DeliverAt, then it's published to Topic.
+type ScheduledMessage struct {
+	ID          uuid.UUID
+	MessageUUID string
+	Topic       string
+	Payload     []byte
+	Metadata    map[string]string
+	DeliverAt   time.Time
+}
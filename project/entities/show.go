@@ -12,4 +12,20 @@ type Show struct {
 	StartTime       time.Time `json:"start_time"`
 	Title           string    `json:"title"`
 	Venue           string    `json:"venue"`
+	TicketPrice     Price     `json:"ticket_price"`
+}
+
+type ShowWithAvailability struct {
+	Show
+	BookedTickets    int `json:"booked_tickets"`
+	RemainingTickets int `json:"remaining_tickets"`
+}
+
+type ShowsFilter struct {
+	Venue     string
+	StartFrom *time.Time
+	StartTo   *time.Time
+
+	Limit  int
+	Offset int
 }
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type StoredEvent struct {
+	EventID       string          `json:"event_id"`
+	EventName     string          `json:"event_name"`
+	Version       int             `json:"version"`
+	CorrelationID string          `json:"correlation_id"`
+	PublishedAt   time.Time       `json:"published_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type StoredEventsFilter struct {
+	EventNames    []string
+	CorrelationID string
+	From          *time.Time
+	To            *time.Time
+	// After returns only events following the given one, in the order of published_at and event_id.
+	// Unlike Offset, it doesn't skip or repeat events when new ones are stored while paging.
+	After *StoredEventCursor
+
+	Limit  int
+	Offset int
+}
+
+// StoredEventCursor is the position of an event in the order of stored events.
+type StoredEventCursor struct {
+	PublishedAt time.Time
+	EventID     string
+}
+
+func (e StoredEvent) Cursor() StoredEventCursor {
+	return StoredEventCursor{PublishedAt: e.PublishedAt, EventID: e.EventID}
+}
@@ -1,8 +1,82 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTicketNotFound                = errors.New("ticket not found")
+	ErrTicketNotPrinted              = errors.New("ticket has not been printed yet")
+	ErrInvalidTicketStatusTransition = errors.New("invalid ticket status transition")
+)
+
+type TicketStatus string
+
+const (
+	TicketStatusConfirmed TicketStatus = "confirmed"
+	TicketStatusCanceled  TicketStatus = "canceled"
+	TicketStatusRefunded  TicketStatus = "refunded"
+)
+
+// ticketStatusTransitions lists the statuses a ticket can move to from a given status.
+// Refunding a confirmed ticket is allowed, as the refund cancels it implicitly.
+var ticketStatusTransitions = map[TicketStatus][]TicketStatus{
+	TicketStatusConfirmed: {TicketStatusCanceled, TicketStatusRefunded},
+	TicketStatusCanceled:  {TicketStatusRefunded},
+	TicketStatusRefunded:  {},
+}
+
+func ParseTicketStatus(status string) (TicketStatus, error) {
+	s := TicketStatus(status)
+	if _, ok := ticketStatusTransitions[s]; !ok {
+		return "", fmt.Errorf("unknown ticket status: %s", status)
+	}
+
+	return s, nil
+}
+
+// CanTransitionTo reports whether a ticket in status s may move to next.
+// Staying in the same status is allowed, so redelivered events are no-ops.
+func (s TicketStatus) CanTransitionTo(next TicketStatus) bool {
+	if s == next {
+		return true
+	}
+
+	for _, allowed := range ticketStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Ticket struct {
-	ID            string `json:"ticket_id"`
-	Status        string `json:"status"`
-	Price         Price  `json:"price"`
-	CustomerEmail string `json:"customer_email"`
+	ID            string       `json:"ticket_id"`
+	Status        TicketStatus `json:"status"`
+	Price         Price        `json:"price"`
+	CustomerEmail string       `json:"customer_email"`
+	Printed       bool         `json:"printed"`
+	CheckedIn     bool         `json:"checked_in"`
+}
+
+func (t Ticket) CanCheckIn() error {
+	if t.Status != TicketStatusConfirmed {
+		return fmt.Errorf("%w: cannot check in %s ticket", ErrInvalidTicketStatusTransition, t.Status)
+	}
+
+	return nil
+}
+
+func (t Ticket) CanRefund() error {
+	if t.Status == TicketStatusRefunded {
+		return fmt.Errorf("%w: ticket is already refunded", ErrInvalidTicketStatusTransition)
+	}
+
+	return nil
+}
+
+type TicketsFilter struct {
+	Status TicketStatus
 }
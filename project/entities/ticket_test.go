@@ -0,0 +1,51 @@
+package entities_test
+
+import (
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketStatusTransitions(t *testing.T) {
+	testCases := []struct {
+		from    entities.TicketStatus
+		to      entities.TicketStatus
+		allowed bool
+	}{
+		{entities.TicketStatusConfirmed, entities.TicketStatusConfirmed, true},
+		{entities.TicketStatusConfirmed, entities.TicketStatusCanceled, true},
+		{entities.TicketStatusConfirmed, entities.TicketStatusRefunded, true},
+		{entities.TicketStatusCanceled, entities.TicketStatusRefunded, true},
+		{entities.TicketStatusCanceled, entities.TicketStatusConfirmed, false},
+		{entities.TicketStatusRefunded, entities.TicketStatusCanceled, false},
+		{entities.TicketStatusRefunded, entities.TicketStatusConfirmed, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"->"+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.allowed, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestParseTicketStatus(t *testing.T) {
+	status, err := entities.ParseTicketStatus("canceled")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.TicketStatusCanceled, status)
+
+	_, err = entities.ParseTicketStatus("completed")
+	assert.Error(t, err)
+}
+
+func TestCheckIn(t *testing.T) {
+	assert.NoError(t, entities.Ticket{Status: entities.TicketStatusConfirmed}.CanCheckIn())
+	assert.ErrorIs(t, entities.Ticket{Status: entities.TicketStatusCanceled}.CanCheckIn(), entities.ErrInvalidTicketStatusTransition)
+}
+
+func TestRefund(t *testing.T) {
+	assert.NoError(t, entities.Ticket{Status: entities.TicketStatusConfirmed}.CanRefund())
+	assert.NoError(t, entities.Ticket{Status: entities.TicketStatusCanceled}.CanRefund())
+	assert.ErrorIs(t, entities.Ticket{Status: entities.TicketStatusRefunded}.CanRefund(), entities.ErrInvalidTicketStatusTransition)
+}
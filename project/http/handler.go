@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"tickets/entities"
 
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
@@ -10,10 +12,17 @@ import (
 
 type Handler struct {
 	eventBus              *cqrs.EventBus
+	commandBus            *cqrs.CommandBus
 	spreadsheetsAPIClient SpreadsheetsAPI
 	ticketRepository      TicketRepository
 	showRepository        ShowRepository
 	bookingRepository     BookingRepository
+	receiptRepository     ReceiptRepository
+	opsBookingsRepository OpsBookingsRepository
+	eventRepository       EventRepository
+	poisonedMessages      PoisonedMessageRepository
+	poisonQueue           PoisonQueue
+	filesAPI              FilesAPI
 }
 
 type SpreadsheetsAPI interface {
@@ -21,11 +30,43 @@ type SpreadsheetsAPI interface {
 }
 
 type TicketRepository interface {
-	All(ctx context.Context) ([]entities.Ticket, error)
+	All(ctx context.Context, filter entities.TicketsFilter) ([]entities.Ticket, error)
+	ByID(ctx context.Context, ticketID string) (entities.Ticket, error)
+	CheckIn(ctx context.Context, ticketID string) error
+	FileName(ctx context.Context, ticketID string) (string, error)
+}
+
+type ReceiptRepository interface {
+	ByTicketID(ctx context.Context, ticketID string) (entities.Receipt, error)
+}
+
+type OpsBookingsRepository interface {
+	All(ctx context.Context, filter entities.OpsBookingsFilter) ([]entities.OpsBooking, error)
+	ByID(ctx context.Context, bookingID uuid.UUID) (entities.OpsBooking, error)
+}
+
+type EventRepository interface {
+	All(ctx context.Context, filter entities.StoredEventsFilter) ([]entities.StoredEvent, error)
+}
+
+type PoisonedMessageRepository interface {
+	All(ctx context.Context, filter entities.PoisonedMessagesFilter) ([]entities.PoisonedMessage, error)
+	ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error)
+	Delete(ctx context.Context, messageUUID string) error
+}
+
+type PoisonQueue interface {
+	Requeue(ctx context.Context, messageUUID string) error
+}
+
+type FilesAPI interface {
+	Download(ctx context.Context, name string) (string, error)
 }
 
 type ShowRepository interface {
 	Create(ctx context.Context, show entities.Show) error
+	All(ctx context.Context, filter entities.ShowsFilter) ([]entities.ShowWithAvailability, error)
+	ByID(ctx context.Context, id uuid.UUID) (entities.ShowWithAvailability, error)
 }
 
 type BookingRepository interface {
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -16,8 +17,14 @@ func (h Handler) CreateBooking(c echo.Context) error {
 	}
 
 	booking.ID = uuid.New()
-	if err := h.bookingRepository.Create(c.Request().Context(), booking); err != nil {
-		return fmt.Errorf("error: error creating booking: %v", err)
+	err := h.bookingRepository.Create(c.Request().Context(), booking)
+	if errors.Is(err, entities.ErrShowNotFound) ||
+		errors.Is(err, entities.ErrNotEnoughTicketsLeft) ||
+		errors.Is(err, entities.ErrInvalidTicketsBooking) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error: error creating booking: %w", err)
 	}
 
 	response := struct {
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"tickets/entities"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+)
+
+func (h Handler) ListEvents(c echo.Context) error {
+	filter := entities.StoredEventsFilter{
+		CorrelationID: c.QueryParam("correlation_id"),
+	}
+	if name := c.QueryParam("name"); name != "" {
+		filter.EventNames = []string{name}
+	}
+
+	var err error
+	if filter.From, err = parseTimeQueryParam(c, "from"); err != nil {
+		return err
+	}
+	if filter.To, err = parseTimeQueryParam(c, "to"); err != nil {
+		return err
+	}
+
+	if filter.Limit, err = parseIntQueryParam(c, "limit", defaultEventsLimit); err != nil {
+		return err
+	}
+	if filter.Limit < 1 || filter.Limit > maxEventsLimit {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxEventsLimit))
+	}
+
+	events, err := h.eventRepository.All(c.Request().Context(), filter)
+	if err != nil {
+		return fmt.Errorf("error fetching events: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h Handler) ListOpsBookings(c echo.Context) error {
+	var filter entities.OpsBookingsFilter
+
+	if date := c.QueryParam("date"); date != "" {
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "date must be in YYYY-MM-DD format")
+		}
+		filter.Date = &d
+	}
+
+	if showID := c.QueryParam("show_id"); showID != "" {
+		id, err := uuid.Parse(showID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid show_id")
+		}
+		filter.ShowID = &id
+	}
+
+	bookings, err := h.opsBookingsRepository.All(c.Request().Context(), filter)
+	if err != nil {
+		return fmt.Errorf("error fetching ops bookings: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, bookings)
+}
+
+func (h Handler) GetOpsBooking(c echo.Context) error {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
+	}
+
+	booking, err := h.opsBookingsRepository.ByID(c.Request().Context(), bookingID)
+	if errors.Is(err, entities.ErrOpsBookingNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching ops booking: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, booking)
+}
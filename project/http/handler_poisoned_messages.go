@@ -0,0 +1,80 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"tickets/entities"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPoisonedMessagesLimit = 100
+	maxPoisonedMessagesLimit     = 1000
+)
+
+func (h Handler) ListPoisonedMessages(c echo.Context) error {
+	filter := entities.PoisonedMessagesFilter{
+		Topic:       c.QueryParam("topic"),
+		HandlerName: c.QueryParam("handler"),
+	}
+
+	var err error
+	if filter.Limit, err = parseIntQueryParam(c, "limit", defaultPoisonedMessagesLimit); err != nil {
+		return err
+	}
+	if filter.Limit < 1 || filter.Limit > maxPoisonedMessagesLimit {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxPoisonedMessagesLimit))
+	}
+	if filter.Offset, err = parseIntQueryParam(c, "offset", 0); err != nil {
+		return err
+	}
+	if filter.Offset < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "offset must not be negative")
+	}
+
+	messages, err := h.poisonedMessages.All(c.Request().Context(), filter)
+	if err != nil {
+		return fmt.Errorf("error fetching poisoned messages: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, messages)
+}
+
+func (h Handler) GetPoisonedMessage(c echo.Context) error {
+	msg, err := h.poisonedMessages.ByUUID(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, entities.ErrPoisonedMessageNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching poisoned message: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, msg)
+}
+
+func (h Handler) RequeuePoisonedMessage(c echo.Context) error {
+	err := h.poisonQueue.Requeue(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, entities.ErrPoisonedMessageNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error requeuing poisoned message: %w", err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h Handler) DeletePoisonedMessage(c echo.Context) error {
+	err := h.poisonedMessages.Delete(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, entities.ErrPoisonedMessageNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting poisoned message: %w", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,20 +1,29 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strconv"
 	"tickets/entities"
 	"time"
 )
 
+const (
+	defaultShowsPerPage = 20
+	maxShowsPerPage     = 100
+)
+
 type createShowRequest struct {
 	DeadNationID    uuid.UUID `json:"dead_nation_id"`
 	NumberOfTickets int       `json:"number_of_tickets"`
 	StartTime       time.Time `json:"start_time"`
 	Title           string    `json:"title"`
 	Venue           string    `json:"venue"`
+	// TicketPrice is optional, shows without it are free
+	TicketPrice *entities.Price `json:"ticket_price"`
 }
 
 func (h Handler) CreateShow(c echo.Context) error {
@@ -23,12 +32,23 @@ func (h Handler) CreateShow(c echo.Context) error {
 		return err
 	}
 
+	// the same default as in the database schema
+	ticketPrice := entities.Price{Amount: entities.NewDecimalFromInt(0), Currency: "USD"}
+	if request.TicketPrice != nil {
+		if err := request.TicketPrice.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		ticketPrice = *request.TicketPrice
+	}
+
 	show := entities.Show{
-		ID:           uuid.New(),
-		DeadNationID: request.DeadNationID,
-		StartTime:    request.StartTime,
-		Title:        request.Title,
-		Venue:        request.Venue,
+		ID:              uuid.New(),
+		DeadNationID:    request.DeadNationID,
+		NumberOfTickets: request.NumberOfTickets,
+		StartTime:       request.StartTime,
+		Title:           request.Title,
+		Venue:           request.Venue,
+		TicketPrice:     ticketPrice,
 	}
 
 	err := h.showRepository.Create(c.Request().Context(), show)
@@ -39,5 +59,89 @@ func (h Handler) CreateShow(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, struct {
 		ShowID string `json:"show_id"`
-	}{ShowID: uuid.NewString()})
+	}{ShowID: show.ID.String()})
+}
+
+func (h Handler) ListShows(c echo.Context) error {
+	filter := entities.ShowsFilter{
+		Venue: c.QueryParam("venue"),
+	}
+
+	var err error
+	if filter.StartFrom, err = parseTimeQueryParam(c, "start_from"); err != nil {
+		return err
+	}
+	if filter.StartTo, err = parseTimeQueryParam(c, "start_to"); err != nil {
+		return err
+	}
+
+	page, err := parseIntQueryParam(c, "page", 1)
+	if err != nil {
+		return err
+	}
+	perPage, err := parseIntQueryParam(c, "per_page", defaultShowsPerPage)
+	if err != nil {
+		return err
+	}
+	if page < 1 || perPage < 1 || perPage > maxShowsPerPage {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			fmt.Sprintf("page must be positive and per_page must be between 1 and %d", maxShowsPerPage),
+		)
+	}
+
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	shows, err := h.showRepository.All(c.Request().Context(), filter)
+	if err != nil {
+		return fmt.Errorf("error fetching shows: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, shows)
+}
+
+func (h Handler) GetShow(c echo.Context) error {
+	showID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid show id")
+	}
+
+	show, err := h.showRepository.ByID(c.Request().Context(), showID)
+	if errors.Is(err, entities.ErrShowNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching show: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, show)
+}
+
+func parseTimeQueryParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be an RFC 3339 timestamp", name))
+	}
+
+	return &t, nil
+}
+
+func parseIntQueryParam(c echo.Context, name string, defaultValue int) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be an integer", name))
+	}
+
+	return i, nil
 }
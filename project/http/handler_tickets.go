@@ -1,10 +1,12 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"tickets/entities"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -29,12 +31,25 @@ func (h Handler) PostTicketsStatus(c echo.Context) error {
 	}
 
 	for _, ticket := range request.Tickets {
+		if err := ticket.Price.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ticket %s: %s", ticket.TicketID, err))
+		}
+	}
+
+	for _, ticket := range request.Tickets {
+		// one request can contain many tickets, their events can't share the idempotency key
+		ticketIdempotencyKey := ""
+		if idempotencyKey != "" {
+			ticketIdempotencyKey = idempotencyKey + ticket.TicketID
+		}
+
 		if ticket.Status == "confirmed" {
 			event := entities.TicketBookingConfirmed{
-				Header:        entities.NewEventHeaderWithIdempotencyKey(idempotencyKey),
+				Header:        entities.NewEventHeaderWithIdempotencyKey(ticketIdempotencyKey),
 				TicketID:      ticket.TicketID,
 				Price:         ticket.Price,
 				CustomerEmail: ticket.CustomerEmail,
+				BookingID:     ticket.BookingID,
 			}
 
 			if err := h.eventBus.Publish(c.Request().Context(), event); err != nil {
@@ -42,7 +57,7 @@ func (h Handler) PostTicketsStatus(c echo.Context) error {
 			}
 		} else if ticket.Status == "canceled" {
 			event := entities.TicketBookingCanceled{
-				Header:        entities.NewEventHeaderWithIdempotencyKey(idempotencyKey),
+				Header:        entities.NewEventHeaderWithIdempotencyKey(ticketIdempotencyKey),
 				TicketID:      ticket.TicketID,
 				CustomerEmail: ticket.CustomerEmail,
 				Price:         ticket.Price,
@@ -60,7 +75,16 @@ func (h Handler) PostTicketsStatus(c echo.Context) error {
 }
 
 func (h Handler) ListTickets(c echo.Context) error {
-	tickets, err := h.ticketRepository.All(c.Request().Context())
+	var filter entities.TicketsFilter
+	if status := c.QueryParam("status"); status != "" {
+		var err error
+		filter.Status, err = entities.ParseTicketStatus(status)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	tickets, err := h.ticketRepository.All(c.Request().Context(), filter)
 
 	if err != nil {
 		return fmt.Errorf("error fetching tickets: %w", err)
@@ -68,3 +92,96 @@ func (h Handler) ListTickets(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, tickets)
 }
+
+func (h Handler) CheckInTicket(c echo.Context) error {
+	err := h.ticketRepository.CheckIn(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, entities.ErrTicketNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, entities.ErrInvalidTicketStatusTransition) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error checking in ticket: %w", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h Handler) GetTicketFile(c echo.Context) error {
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid ticket id")
+	}
+
+	fileName, err := h.ticketRepository.FileName(c.Request().Context(), ticketID)
+	if errors.Is(err, entities.ErrTicketNotFound) || errors.Is(err, entities.ErrTicketNotPrinted) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching ticket file name: %w", err)
+	}
+
+	content, err := h.filesAPI.Download(c.Request().Context(), fileName)
+	if err != nil {
+		return fmt.Errorf("error downloading ticket file %s: %w", fileName, err)
+	}
+	if content == "" {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("file %s not found", fileName))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", fileName))
+
+	return c.Blob(http.StatusOK, echo.MIMETextHTMLCharsetUTF8, []byte(content))
+}
+
+func (h Handler) GetTicketReceipt(c echo.Context) error {
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid ticket id")
+	}
+
+	receipt, err := h.receiptRepository.ByTicketID(c.Request().Context(), ticketID)
+	if errors.Is(err, entities.ErrReceiptNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching receipt: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, receipt)
+}
+
+func (h Handler) RefundTicket(c echo.Context) error {
+	ticketID := c.Param("ticket_id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid ticket id")
+	}
+
+	ticket, err := h.ticketRepository.ByID(c.Request().Context(), ticketID)
+	if errors.Is(err, entities.ErrTicketNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching ticket: %w", err)
+	}
+
+	if err := ticket.CanRefund(); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	header := entities.NewEventHeader()
+	if idempotencyKey := c.Request().Header.Get("Idempotency-Key"); idempotencyKey != "" {
+		header = entities.NewEventHeaderWithIdempotencyKey(idempotencyKey)
+	}
+
+	err = h.commandBus.Send(c.Request().Context(), entities.RefundTicket{
+		Header:   header,
+		TicketID: ticketID,
+	})
+	if err != nil {
+		return fmt.Errorf("error sending refund ticket command: %w", err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
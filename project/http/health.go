@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HealthCheck checks one dependency of the service.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Optional checks are reported, but the service is ready even when they fail, for example external APIs.
+	Optional bool
+}
+
+// Health serves the liveness and readiness probes.
+//
+// The service is live as long as it serves HTTP requests.
+// It's ready when all required checks pass, and it's not shutting down.
+type Health struct {
+	checks       []HealthCheck
+	checkTimeout time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewHealth(checkTimeout time.Duration, checks ...HealthCheck) *Health {
+	return &Health{
+		checks:       checks,
+		checkTimeout: checkTimeout,
+	}
+}
+
+// ShuttingDown makes the service not ready, so no new traffic is sent to it.
+func (h *Health) ShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+const (
+	healthStatusOK      = "ok"
+	healthStatusFailing = "failing"
+)
+
+var errShuttingDown = errors.New("service is shutting down")
+
+type healthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]healthCheckResult `json:"checks,omitempty"`
+}
+
+type healthCheckResult struct {
+	Status    string  `json:"status"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func (h *Health) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, healthResponse{Status: healthStatusOK})
+}
+
+// Ready runs all checks in parallel, every one of them is limited by the check timeout.
+func (h *Health) Ready(c echo.Context) error {
+	response := healthResponse{
+		Status: healthStatusOK,
+		Checks: make(map[string]healthCheckResult, len(h.checks)+1),
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		check := check
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := h.run(c.Request().Context(), check)
+
+			lock.Lock()
+			defer lock.Unlock()
+			response.Checks[check.Name] = result
+		}()
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		response.Checks["shutdown"] = healthCheckResult{Status: healthStatusFailing, Error: errShuttingDown.Error()}
+	}
+
+	for _, result := range response.Checks {
+		if result.Status == healthStatusFailing && !result.Optional {
+			response.Status = healthStatusFailing
+		}
+	}
+
+	statusCode := http.StatusOK
+	if response.Status != healthStatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(statusCode, response)
+}
+
+func (h *Health) run(ctx context.Context, check HealthCheck) healthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+
+	result := healthCheckResult{
+		Status:    healthStatusOK,
+		Optional:  check.Optional,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = healthStatusFailing
+		result.Error = err.Error()
+	}
+
+	return result
+}
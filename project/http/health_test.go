@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	postgresErr := error(nil)
+
+	health := NewHealth(
+		time.Second,
+		HealthCheck{Name: "postgres", Check: func(ctx context.Context) error { return postgresErr }},
+		HealthCheck{Name: "gateway", Check: func(ctx context.Context) error { return errors.New("connection refused") }, Optional: true},
+	)
+
+	ready := func() (int, healthResponse) {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/health/ready", nil), rec)
+		require.NoError(t, health.Ready(c))
+
+		var response healthResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		return rec.Code, response
+	}
+
+	statusCode, response := ready()
+	assert.Equal(t, http.StatusOK, statusCode, "failing optional checks don't make the service unready")
+	assert.Equal(t, healthStatusOK, response.Status)
+	assert.Equal(t, healthStatusOK, response.Checks["postgres"].Status)
+	assert.Equal(t, healthStatusFailing, response.Checks["gateway"].Status)
+	assert.Equal(t, "connection refused", response.Checks["gateway"].Error)
+
+	postgresErr = errors.New("connection reset")
+	statusCode, response = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	assert.Equal(t, healthStatusFailing, response.Status)
+	assert.Equal(t, "connection reset", response.Checks["postgres"].Error)
+
+	postgresErr = nil
+	health.ShuttingDown()
+	statusCode, response = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	assert.Equal(t, healthStatusFailing, response.Checks["shutdown"].Status)
+}
+
+func TestHealth_Ready_timeout(t *testing.T) {
+	health := NewHealth(10*time.Millisecond, HealthCheck{Name: "redis", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/health/ready", nil), rec)
+	require.NoError(t, health.Ready(c))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "deadline exceeded")
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/labstack/echo/v4"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type IdempotencyKeyRepository interface {
+	Start(ctx context.Context, idempotencyKey string, requestHash string) (*entities.IdempotentResponse, error)
+	Complete(ctx context.Context, idempotencyKey string, response entities.IdempotentResponse) error
+	Release(ctx context.Context, idempotencyKey string) error
+}
+
+// IdempotencyMiddleware makes requests with the Idempotency-Key header safe to retry.
+//
+// The response of the first request is stored and replayed for every request with the same key.
+// Reusing the key for a different request returns 422, and repeating a request which is still in progress returns 409.
+// Failed requests (errors and 5xx responses) are not stored, so they can be retried with the same key.
+func IdempotencyMiddleware(repository IdempotencyKeyRepository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idempotencyKey := c.Request().Header.Get(idempotencyKeyHeader)
+			if idempotencyKey == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return fmt.Errorf("could not read request body: %w", err)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			storedResponse, err := repository.Start(ctx, idempotencyKey, requestHash(c.Request(), body))
+			if errors.Is(err, entities.ErrIdempotencyKeyReused) {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+			}
+			if errors.Is(err, entities.ErrIdempotencyKeyInProgress) {
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			}
+			if err != nil {
+				return err
+			}
+
+			if storedResponse != nil {
+				c.Response().Header().Set("Idempotent-Replayed", "true")
+				if storedResponse.ContentType == "" {
+					return c.NoContent(storedResponse.StatusCode)
+				}
+				return c.Blob(storedResponse.StatusCode, storedResponse.ContentType, storedResponse.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+
+			// the response is already sent, so it has to be stored even if the client went away
+			ctx = context.WithoutCancel(ctx)
+
+			if handlerErr != nil || c.Response().Status >= http.StatusInternalServerError {
+				if err := repository.Release(ctx, idempotencyKey); err != nil {
+					log.FromContext(ctx).WithError(err).Error("Could not release idempotency key")
+				}
+				return handlerErr
+			}
+
+			err = repository.Complete(ctx, idempotencyKey, entities.IdempotentResponse{
+				StatusCode:  c.Response().Status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Error("Could not store idempotent response")
+			}
+
+			return nil
+		}
+	}
+}
+
+// requestHash identifies the request, so the same key can't be used for another endpoint or payload.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
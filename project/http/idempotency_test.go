@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type idempotencyKeyRepositoryMock struct {
+	lock      sync.Mutex
+	hashes    map[string]string
+	responses map[string]entities.IdempotentResponse
+}
+
+func (m *idempotencyKeyRepositoryMock) Start(ctx context.Context, idempotencyKey string, requestHash string) (*entities.IdempotentResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hash, ok := m.hashes[idempotencyKey]
+	if !ok {
+		m.hashes[idempotencyKey] = requestHash
+		return nil, nil
+	}
+	if hash != requestHash {
+		return nil, entities.ErrIdempotencyKeyReused
+	}
+
+	response, ok := m.responses[idempotencyKey]
+	if !ok {
+		return nil, entities.ErrIdempotencyKeyInProgress
+	}
+
+	return &response, nil
+}
+
+func (m *idempotencyKeyRepositoryMock) Complete(ctx context.Context, idempotencyKey string, response entities.IdempotentResponse) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.responses[idempotencyKey] = response
+	return nil
+}
+
+func (m *idempotencyKeyRepositoryMock) Release(ctx context.Context, idempotencyKey string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.hashes, idempotencyKey)
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	repository := &idempotencyKeyRepositoryMock{
+		hashes:    map[string]string{},
+		responses: map[string]entities.IdempotentResponse{},
+	}
+
+	calls := 0
+	e := echo.New()
+	e.POST("/shows", func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	}, IdempotencyMiddleware(repository))
+
+	send := func(idempotencyKey string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/shows", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if idempotencyKey != "" {
+			req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send("key", `{"title":"show"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replayed := send("key", `{"title":"show"}`)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send("key", `{"title":"other show"}`).Code)
+
+	repository.hashes["in-progress"] = requestHash(httptest.NewRequest(http.MethodPost, "/shows", nil), []byte(`{}`))
+	assert.Equal(t, http.StatusConflict, send("in-progress", `{}`).Code)
+
+	assert.Equal(t, http.StatusCreated, send("", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
@@ -10,29 +10,65 @@ import (
 
 func NewHttpRouter(
 	eventBus *cqrs.EventBus,
+	commandBus *cqrs.CommandBus,
 	spreadsheetsAPIClient SpreadsheetsAPI,
 	ticketRepository TicketRepository,
 	showRepository ShowRepository,
 	bookingRepository BookingRepository,
+	receiptRepository ReceiptRepository,
+	opsBookingsRepository OpsBookingsRepository,
+	eventRepository EventRepository,
+	idempotencyKeyRepository IdempotencyKeyRepository,
+	poisonedMessageRepository PoisonedMessageRepository,
+	poisonQueue PoisonQueue,
+	filesAPI FilesAPI,
+	health *Health,
 ) *echo.Echo {
 	e := libHttp.NewEcho()
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
+	e.GET("/health/live", health.Live)
+	e.GET("/health/ready", health.Ready)
 
 	handler := Handler{
 		eventBus:              eventBus,
+		commandBus:            commandBus,
 		spreadsheetsAPIClient: spreadsheetsAPIClient,
 		ticketRepository:      ticketRepository,
 		showRepository:        showRepository,
 		bookingRepository:     bookingRepository,
+		receiptRepository:     receiptRepository,
+		opsBookingsRepository: opsBookingsRepository,
+		eventRepository:       eventRepository,
+		poisonedMessages:      poisonedMessageRepository,
+		poisonQueue:           poisonQueue,
+		filesAPI:              filesAPI,
 	}
 
-	e.POST("/tickets-status", handler.PostTicketsStatus)
+	idempotency := IdempotencyMiddleware(idempotencyKeyRepository)
+
+	e.POST("/tickets-status", handler.PostTicketsStatus, idempotency)
 	e.GET("/tickets", handler.ListTickets)
-	e.POST("/shows", handler.CreateShow)
-	e.POST("/book-tickets", handler.CreateBooking)
+	e.POST("/tickets/:id/check-in", handler.CheckInTicket, idempotency)
+	e.GET("/tickets/:id/file", handler.GetTicketFile)
+	e.GET("/tickets/:id/receipt", handler.GetTicketReceipt)
+	e.PUT("/ticket-refund/:ticket_id", handler.RefundTicket, idempotency)
+	e.POST("/shows", handler.CreateShow, idempotency)
+	e.GET("/shows", handler.ListShows)
+	e.GET("/shows/:id", handler.GetShow)
+	e.POST("/book-tickets", handler.CreateBooking, idempotency)
+
+	e.GET("/ops/bookings", handler.ListOpsBookings)
+	e.GET("/ops/bookings/:id", handler.GetOpsBooking)
+
+	e.GET("/events", handler.ListEvents)
+
+	e.GET("/admin/poisoned-messages", handler.ListPoisonedMessages)
+	e.GET("/admin/poisoned-messages/:id", handler.GetPoisonedMessage)
+	e.POST("/admin/poisoned-messages/:id/requeue", handler.RequeuePoisonedMessage)
+	e.DELETE("/admin/poisoned-messages/:id", handler.DeletePoisonedMessage)
 
 	return e
 }
@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"net/http"
 	"os"
 	"os/signal"
 
 	"tickets/api"
-	"tickets/message"
+	"tickets/config"
 	"tickets/service"
 
 	"database/sql"
@@ -21,8 +23,18 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Could not load config")
+	}
+
+	log.FromContext(ctx).Infof("Effective config:\n%s", cfg.Redacted())
+
 	apiClients, err := clients.NewClients(
-		os.Getenv("GATEWAY_ADDR"),
+		cfg.GatewayAddr,
 		func(ctx context.Context, req *http.Request) error {
 			req.Header.Set("Correlation-ID", log.CorrelationIDFromContext(ctx))
 			return nil
@@ -32,29 +44,59 @@ func main() {
 		panic(err)
 	}
 
-	redisClient := message.NewRedisClient(os.Getenv("REDIS_ADDR"))
-	defer redisClient.Close()
-
 	spreadsheetsService := api.NewSpreadsheetsAPIClient(apiClients)
 	receiptsService := api.NewReceiptsServiceClient(apiClients)
 	filesAPI := api.NewFilesAPIClient(apiClients)
+	deadNationAPI := api.NewDeadNationAPIClient(apiClients)
+	paymentsService := api.NewPaymentsServiceClient(apiClients)
+
+	var postgres *pgxpool.Pool
+	var stdLibDB *sql.DB
+	if cfg.UsesPostgres() {
+		postgres, err = pgxpool.New(context.Background(), cfg.Postgres.URL.Value())
+		if err != nil {
+			panic(err)
+		}
+
+		stdLibDB, err = sql.Open("postgres", cfg.Postgres.URL.Value())
+		if err != nil {
+			panic(err)
+		}
+	}
 
-	postgres, err := pgxpool.New(context.Background(), os.Getenv("POSTGRES_URL"))
+	broker, err := service.NewBroker(cfg, stdLibDB)
 	if err != nil {
 		panic(err)
 	}
-	defer postgres.Close()
-
-	stdLibDB, err := sql.Open("postgres", os.Getenv("POSTGRES_URL"))
 
-	err = service.New(
-		redisClient,
+	// the service closes the broker and the databases when it stops
+	svc := service.New(
+		cfg,
+		broker,
 		postgres,
 		stdLibDB,
 		spreadsheetsService,
 		receiptsService,
 		filesAPI,
-	).Run(ctx)
+		deadNationAPI,
+		paymentsService,
+	)
+
+	if len(args) > 0 && args[0] == "replay" {
+		replayConfig, err := parseReplayConfig(args[1:])
+		if err != nil {
+			panic(err)
+		}
+
+		err = svc.Replay(ctx, replayConfig)
+		if err != nil {
+			panic(err)
+		}
+
+		return
+	}
+
+	err = svc.Run(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,103 @@
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+)
+
+// BrokerBackend is the Pub/Sub used to deliver events and commands.
+type BrokerBackend string
+
+const (
+	BrokerRedis    BrokerBackend = "redis"
+	BrokerPostgres BrokerBackend = "postgres"
+	// BrokerGoChannel delivers messages in-process, they are lost when the service stops.
+	BrokerGoChannel BrokerBackend = "gochannel"
+)
+
+func ParseBrokerBackend(v string) (BrokerBackend, error) {
+	switch backend := BrokerBackend(v); backend {
+	case BrokerRedis, BrokerPostgres, BrokerGoChannel:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("unknown broker %q, expected %q, %q or %q", v, BrokerRedis, BrokerPostgres, BrokerGoChannel)
+	}
+}
+
+// Broker creates publishers and subscribers of one backend.
+// Topics and consumer groups are in the broker's namespace, and published messages get the correlation ID.
+type Broker struct {
+	publisher     message.Publisher
+	newSubscriber func(consumerGroup string) (message.Subscriber, error)
+	namespace     Namespace
+	close         func() error
+	ping          func(ctx context.Context) error
+}
+
+func newBroker(
+	publisher message.Publisher,
+	newSubscriber func(consumerGroup string) (message.Subscriber, error),
+	namespace Namespace,
+	close func() error,
+	ping func(ctx context.Context) error,
+) *Broker {
+	return &Broker{
+		publisher: NamespacedPublisher{
+			Publisher: log.CorrelationPublisherDecorator{Publisher: publisher},
+			Namespace: namespace,
+		},
+		newSubscriber: newSubscriber,
+		namespace:     namespace,
+		close:         close,
+		ping:          ping,
+	}
+}
+
+// NewGoChannelBroker delivers messages in-process.
+// Every subscriber gets all messages of its topic, so consumer groups only work when each of them subscribes once,
+// which is the case for router handlers.
+func NewGoChannelBroker(namespace Namespace, logger watermill.LoggerAdapter) *Broker {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, logger)
+
+	return newBroker(
+		pubSub,
+		func(string) (message.Subscriber, error) {
+			return pubSub, nil
+		},
+		namespace,
+		pubSub.Close,
+		// messages are delivered in-process, there is nothing to ping
+		func(context.Context) error { return nil },
+	)
+}
+
+func (b *Broker) Publisher() message.Publisher {
+	return b.publisher
+}
+
+func (b *Broker) Subscriber(consumerGroup string) (message.Subscriber, error) {
+	sub, err := b.newSubscriber(b.namespace.Prefix(consumerGroup))
+	if err != nil {
+		return nil, fmt.Errorf("could not create subscriber of consumer group %s: %w", consumerGroup, err)
+	}
+
+	return NamespacedSubscriber{Subscriber: sub, Namespace: b.namespace}, nil
+}
+
+// Ping checks the connection to the broker's backend.
+func (b *Broker) Ping(ctx context.Context) error {
+	return b.ping(ctx)
+}
+
+func (b *Broker) Close() error {
+	if err := b.publisher.Close(); err != nil {
+		return err
+	}
+
+	return b.close()
+}
@@ -0,0 +1,54 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBrokerBackend(t *testing.T) {
+	for _, backend := range []BrokerBackend{BrokerRedis, BrokerPostgres, BrokerGoChannel} {
+		parsed, err := ParseBrokerBackend(string(backend))
+		require.NoError(t, err)
+		assert.Equal(t, backend, parsed)
+	}
+
+	_, err := ParseBrokerBackend("kafka")
+	assert.Error(t, err)
+}
+
+func TestGoChannelBroker(t *testing.T) {
+	broker := NewGoChannelBroker("staging", watermill.NopLogger{})
+	defer broker.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []<-chan *message.Message
+	for _, consumerGroup := range []string{"svc-tickets.A", "svc-tickets.B"} {
+		sub, err := broker.Subscriber(consumerGroup)
+		require.NoError(t, err)
+
+		messages, err := sub.Subscribe(ctx, "BookingMade")
+		require.NoError(t, err)
+		received = append(received, messages)
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte("{}"))
+	require.NoError(t, broker.Publisher().Publish("BookingMade", msg))
+
+	for _, messages := range received {
+		select {
+		case m := <-messages:
+			assert.Equal(t, msg.UUID, m.UUID)
+			m.Ack()
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+	}
+}
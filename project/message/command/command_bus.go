@@ -0,0 +1,23 @@
+package command
+
+import (
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+func NewCommandBus(pub message.Publisher) *cqrs.CommandBus {
+	commandBus, err := cqrs.NewCommandBusWithConfig(
+		pub,
+		cqrs.CommandBusConfig{
+			GeneratePublishTopic: func(params cqrs.CommandBusGeneratePublishTopicParams) (string, error) {
+				return "commands." + params.CommandName, nil
+			},
+			Marshaler: JSONMarshaler,
+		})
+
+	if err != nil {
+		panic(err)
+	}
+
+	return commandBus
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	ticketsMessage "tickets/message"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+var JSONMarshaler = cqrs.JSONMarshaler{
+	GenerateName: cqrs.StructName,
+}
+
+func NewProcessorConfig(
+	broker *ticketsMessage.Broker,
+	consumerGroupPrefix string,
+	watermillLogger watermill.LoggerAdapter,
+) cqrs.CommandProcessorConfig {
+	return cqrs.CommandProcessorConfig{
+		GenerateSubscribeTopic: func(params cqrs.CommandProcessorGenerateSubscribeTopicParams) (string, error) {
+			return "commands." + params.CommandName, nil
+		},
+		SubscriberConstructor: func(params cqrs.CommandProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+			return broker.Subscriber(consumerGroupPrefix + ".commands." + params.HandlerName)
+		},
+		Marshaler: JSONMarshaler,
+		Logger:    watermillLogger,
+	}
+}
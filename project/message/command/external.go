@@ -0,0 +1,15 @@
+package command
+
+import (
+	"context"
+
+	"tickets/entities"
+)
+
+type PaymentsService interface {
+	RefundPayment(ctx context.Context, refund entities.PaymentRefund) error
+}
+
+type ReceiptsService interface {
+	VoidReceipt(ctx context.Context, request entities.VoidReceipt) error
+}
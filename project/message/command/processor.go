@@ -0,0 +1,36 @@
+package command
+
+import (
+	ticketsMessage "tickets/message"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+func RegisterCommandHandlers(
+	router *message.Router,
+	config cqrs.CommandProcessorConfig,
+	paymentsService PaymentsService,
+	receiptsService ReceiptsService,
+	eventBus *cqrs.EventBus,
+	handlerPolicies *ticketsMessage.HandlerPolicies,
+) *cqrs.CommandProcessor {
+	commandProcessor, err := cqrs.NewCommandProcessorWithConfig(router, config)
+	if err != nil {
+		panic(err)
+	}
+
+	handlers := []cqrs.CommandHandler{
+		NewRefundTicketHandler(paymentsService, receiptsService, eventBus),
+	}
+	for _, handler := range handlers {
+		handlerPolicies.Register(handler)
+	}
+
+	err = commandProcessor.AddHandlers(handlers...)
+	if err != nil {
+		panic(err)
+	}
+
+	return commandProcessor
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+)
+
+type RefundTicketHandler struct {
+	paymentsService PaymentsService
+	receiptsService ReceiptsService
+	eventBus        *cqrs.EventBus
+}
+
+func NewRefundTicketHandler(
+	paymentsService PaymentsService,
+	receiptsService ReceiptsService,
+	eventBus *cqrs.EventBus,
+) *RefundTicketHandler {
+	return &RefundTicketHandler{
+		paymentsService: paymentsService,
+		receiptsService: receiptsService,
+		eventBus:        eventBus,
+	}
+}
+
+func (handler *RefundTicketHandler) HandlerName() string {
+	return "RefundTicket"
+}
+
+func (handler *RefundTicketHandler) NewCommand() interface{} {
+	return &entities.RefundTicket{}
+}
+
+func (handler *RefundTicketHandler) Handle(ctx context.Context, command any) error {
+	refundTicket, ok := command.(*entities.RefundTicket)
+	if !ok {
+		return fmt.Errorf("unexpected command type: %T", command)
+	}
+	log.FromContext(ctx).Infof("Refunding ticket: '%s'", refundTicket.TicketID)
+
+	// a ticket can be refunded only once, so repeated refund requests must not refund the payment again
+	idempotencyKey := "refund-" + refundTicket.TicketID
+
+	err := handler.paymentsService.RefundPayment(ctx, entities.PaymentRefund{
+		TicketID:       refundTicket.TicketID,
+		RefundReason:   "customer requested refund",
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	err = handler.receiptsService.VoidReceipt(ctx, entities.VoidReceipt{
+		TicketID:       refundTicket.TicketID,
+		Reason:         "ticket refunded",
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to void receipt: %w", err)
+	}
+
+	err = handler.eventBus.Publish(ctx, entities.TicketRefunded{
+		Header:   entities.NewEventHeaderWithIdempotencyKey(idempotencyKey),
+		TicketID: refundTicket.TicketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish ticket refunded event: %w", err)
+	}
+
+	return nil
+}
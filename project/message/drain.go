@@ -0,0 +1,174 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// ErrShuttingDown is the error of messages which weren't handled because the service is shutting down.
+// They are nacked instead of being retried or poisoned, so they are redelivered after the restart.
+var ErrShuttingDown = errors.New("service is shutting down")
+
+const drainPollInterval = time.Millisecond * 10
+
+// Drain lets the router finish in-flight messages when the service shuts down.
+//
+// After Start, handlers nack new messages, except the handlers which should keep running, like the outbox forwarder.
+// When Wait times out, contexts of messages which are still handled are canceled with ErrShuttingDown as the cause.
+type Drain struct {
+	lock     sync.Mutex
+	draining bool
+	// except are handlers which keep handling messages while draining
+	except map[string]bool
+	// inFlight counts messages handled right now by the handler
+	inFlight map[string]int
+	// lastHandled is when the handler finished its last message
+	lastHandled map[string]time.Time
+	stats       DrainStats
+
+	abortCtx context.Context
+	abort    context.CancelFunc
+}
+
+// DrainStats summarizes messages handled since the drain started.
+type DrainStats struct {
+	// Finished messages were handled after the drain started.
+	Finished int
+	// Nacked messages arrived after the drain started and were not handled.
+	Nacked int
+	// Aborted messages were still handled when the drain timed out.
+	Aborted int
+}
+
+func NewDrain(exceptHandlers ...string) *Drain {
+	except := make(map[string]bool, len(exceptHandlers))
+	for _, name := range exceptHandlers {
+		except[name] = true
+	}
+
+	abortCtx, abort := context.WithCancel(context.Background())
+
+	return &Drain{
+		except:      except,
+		inFlight:    map[string]int{},
+		lastHandled: map[string]time.Time{},
+		abortCtx:    abortCtx,
+		abort:       abort,
+	}
+}
+
+func (d *Drain) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		handlerName := message.HandlerNameFromCtx(msg.Context())
+
+		if !d.begin(handlerName) {
+			return nil, ErrShuttingDown
+		}
+
+		ctx, cancel := context.WithCancelCause(msg.Context())
+		defer cancel(nil)
+		stopAbort := context.AfterFunc(d.abortCtx, func() {
+			cancel(ErrShuttingDown)
+		})
+		defer stopAbort()
+
+		msg.SetContext(ctx)
+		events, err := h(msg)
+
+		d.end(handlerName, errors.Is(context.Cause(ctx), ErrShuttingDown))
+
+		return events, err
+	}
+}
+
+func (d *Drain) begin(handlerName string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.draining && !d.except[handlerName] {
+		d.stats.Nacked++
+		return false
+	}
+
+	d.inFlight[handlerName]++
+	return true
+}
+
+func (d *Drain) end(handlerName string, aborted bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.inFlight[handlerName]--
+	d.lastHandled[handlerName] = time.Now()
+
+	if !d.draining {
+		return
+	}
+	if aborted {
+		d.stats.Aborted++
+	} else {
+		d.stats.Finished++
+	}
+}
+
+// Start makes handlers nack new messages.
+func (d *Drain) Start() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.draining = true
+}
+
+// Wait waits until handlers finish their in-flight messages.
+// When ctx is done first, the in-flight messages are aborted, and ctx's error is returned.
+func (d *Drain) Wait(ctx context.Context) error {
+	return d.poll(ctx, func() bool {
+		for handlerName, inFlight := range d.inFlight {
+			if inFlight > 0 && !d.except[handlerName] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// WaitIdle waits until the handler didn't handle any message for the idle duration,
+// for example to let the outbox forwarder flush the outbox.
+func (d *Drain) WaitIdle(ctx context.Context, handlerName string, idle time.Duration) error {
+	return d.poll(ctx, func() bool {
+		return d.inFlight[handlerName] == 0 && time.Since(d.lastHandled[handlerName]) >= idle
+	})
+}
+
+func (d *Drain) poll(ctx context.Context, done func() bool) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.lock.Lock()
+		isDone := done()
+		d.lock.Unlock()
+
+		if isDone {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			d.abort()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Drain) Stats() DrainStats {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.stats
+}
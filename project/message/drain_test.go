@@ -0,0 +1,72 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	drain := NewDrain()
+	policy := HandlerPolicy{MaxRetries: 3, InitialInterval: time.Millisecond}
+
+	started := make(chan struct{})
+	handler := drain.Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		return policy.handle(func(msg *message.Message) ([]*message.Message, error) {
+			close(started)
+			<-msg.Context().Done()
+			return nil, msg.Context().Err()
+		}, msg)
+	})
+
+	handlerErr := make(chan error)
+	go func() {
+		_, err := handler(message.NewMessage(watermill.NewUUID(), nil))
+		handlerErr <- err
+	}()
+	<-started
+
+	drain.Start()
+
+	_, err := handler(message.NewMessage(watermill.NewUUID(), nil))
+	assert.ErrorIs(t, err, ErrShuttingDown, "new messages should be nacked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, drain.Wait(ctx), context.DeadlineExceeded)
+
+	select {
+	case err := <-handlerErr:
+		assert.ErrorIs(t, err, ErrShuttingDown, "aborted message should be nacked without retries")
+	case <-time.After(time.Second):
+		t.Fatal("in-flight message was not aborted")
+	}
+
+	assert.Equal(t, DrainStats{Nacked: 1, Aborted: 1}, drain.Stats())
+}
+
+func TestDrain_waitsForInFlightMessages(t *testing.T) {
+	drain := NewDrain()
+
+	started := make(chan struct{})
+	handler := drain.Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	})
+
+	go func() {
+		_, _ = handler(message.NewMessage(watermill.NewUUID(), nil))
+	}()
+	<-started
+
+	drain.Start()
+	require.NoError(t, drain.Wait(context.Background()))
+
+	assert.Equal(t, DrainStats{Finished: 1}, drain.Stats())
+}
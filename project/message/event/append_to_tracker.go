@@ -39,7 +39,7 @@ func (handler *AppendToTrackerHandler) Handle(ctx context.Context, event any) er
 		[]string{
 			ticketBooking.TicketID,
 			ticketBooking.CustomerEmail,
-			ticketBooking.Price.Amount,
+			ticketBooking.Price.Amount.String(),
 			ticketBooking.Price.Currency,
 		},
 	)
@@ -0,0 +1,53 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+type BookPlacesInDeadNationHandler struct {
+	showsRepository ShowsRepository
+	deadNationAPI   DeadNationAPI
+}
+
+func NewBookPlacesInDeadNationHandler(showsRepository ShowsRepository, deadNationAPI DeadNationAPI) *BookPlacesInDeadNationHandler {
+	return &BookPlacesInDeadNationHandler{showsRepository: showsRepository, deadNationAPI: deadNationAPI}
+}
+
+func (handler *BookPlacesInDeadNationHandler) HandlerName() string {
+	return "BookPlacesInDeadNation"
+}
+
+func (handler *BookPlacesInDeadNationHandler) NewEvent() interface{} {
+	return &entities.BookingMade{}
+}
+
+func (handler *BookPlacesInDeadNationHandler) Handle(ctx context.Context, event any) error {
+	bookingMade, ok := event.(*entities.BookingMade)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+	log.FromContext(ctx).Infof("Booking places in Dead Nation: '%s'", bookingMade.BookingID)
+
+	show, err := handler.showsRepository.ByID(ctx, bookingMade.ShowId)
+	if err != nil {
+		return fmt.Errorf("failed to get show %s: %w", bookingMade.ShowId, err)
+	}
+
+	err = handler.deadNationAPI.BookInDeadNation(ctx, entities.DeadNationBooking{
+		BookingID:         bookingMade.BookingID,
+		DeadNationEventID: show.DeadNationID,
+		NumberOfTickets:   bookingMade.NumberOfTickets,
+		CustomerEmail:     bookingMade.CustomerEmail,
+		IdempotencyKey:    bookingMade.Header.IdempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to book places in Dead Nation: %w", err)
+	}
+
+	return nil
+}
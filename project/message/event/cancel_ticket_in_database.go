@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+type CancelTicketInDatabaseHandler struct {
+	repository TicketsRepository
+}
+
+func NewCancelTicketInDatabaseHandler(repository TicketsRepository) *CancelTicketInDatabaseHandler {
+	return &CancelTicketInDatabaseHandler{repository: repository}
+}
+
+func (handler *CancelTicketInDatabaseHandler) HandlerName() string {
+	return "CancelTicketInDatabase"
+}
+
+func (handler *CancelTicketInDatabaseHandler) NewEvent() interface{} {
+	return &entities.TicketBookingCanceled{}
+}
+
+func (handler *CancelTicketInDatabaseHandler) Handle(ctx context.Context, event any) error {
+	log.FromContext(ctx).Info("Canceling ticket in database")
+
+	ticketBooking, ok := event.(*entities.TicketBookingCanceled)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+
+	err := handler.repository.Cancel(ctx, &entities.Ticket{
+		ID:            ticketBooking.TicketID,
+		Price:         ticketBooking.Price,
+		CustomerEmail: ticketBooking.CustomerEmail,
+	})
+	if errors.Is(err, entities.ErrInvalidTicketStatusTransition) {
+		// retrying won't help, the ticket has already moved past this state
+		log.FromContext(ctx).WithError(err).Warn("Ignoring ticket cancellation")
+		return nil
+	}
+
+	return err
+}
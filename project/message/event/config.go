@@ -1,29 +1,34 @@
 package event
 
 import (
+	ticketsMessage "tickets/message"
+
 	"github.com/ThreeDotsLabs/watermill"
-	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/ThreeDotsLabs/watermill/message"
-	"github.com/redis/go-redis/v9"
 )
 
-var JSONMarshaler = cqrs.JSONMarshaler{
-	GenerateName: cqrs.StructName,
+var JSONMarshaler = VersionedMarshaler{
+	JSONMarshaler: cqrs.JSONMarshaler{
+		GenerateName: cqrs.StructName,
+	},
+	Schemas: eventSchemas,
 }
 
-func NewProcessorConfig(redisClient *redis.Client, watermillLogger watermill.LoggerAdapter) cqrs.EventProcessorConfig {
+func NewProcessorConfig(
+	broker *ticketsMessage.Broker,
+	consumerGroupPrefix string,
+	watermillLogger watermill.LoggerAdapter,
+	marshaler cqrs.CommandEventMarshaler,
+) cqrs.EventProcessorConfig {
 	return cqrs.EventProcessorConfig{
 		GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
 			return params.EventName, nil
 		},
 		SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
-			return redisstream.NewSubscriber(redisstream.SubscriberConfig{
-				Client:        redisClient,
-				ConsumerGroup: "svc-tickets." + params.HandlerName,
-			}, watermillLogger)
+			return broker.Subscriber(consumerGroupPrefix + "." + params.HandlerName)
 		},
-		Marshaler: JSONMarshaler,
+		Marshaler: marshaler,
 		Logger:    watermillLogger,
 	}
 }
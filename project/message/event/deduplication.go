@@ -0,0 +1,64 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+)
+
+// DeduplicatedHandler skips events already processed by the wrapped handler.
+//
+// Events are marked as processed only after they are handled successfully, so a failed event is retried.
+// It narrows down duplicates caused by redeliveries, but a crash right after handling can still process the event twice.
+type DeduplicatedHandler struct {
+	cqrs.EventHandler
+	repository ProcessedEventsRepository
+}
+
+func NewDeduplicatedHandler(handler cqrs.EventHandler, repository ProcessedEventsRepository) DeduplicatedHandler {
+	if handler == nil {
+		panic("handler is required")
+	}
+	if repository == nil {
+		panic("processed events repository is required")
+	}
+
+	return DeduplicatedHandler{
+		EventHandler: handler,
+		repository:   repository,
+	}
+}
+
+func (h DeduplicatedHandler) Handle(ctx context.Context, event any) error {
+	e, ok := event.(entities.Event)
+	if !ok {
+		return h.EventHandler.Handle(ctx, event)
+	}
+
+	handlerName := h.HandlerName()
+	idempotencyKey := e.GetHeader().DeduplicationKey()
+
+	processed, err := h.repository.IsProcessed(ctx, handlerName, idempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	if processed {
+		log.FromContext(ctx).WithField("idempotency_key", idempotencyKey).Infof("Skipping event already processed by %s", handlerName)
+		return nil
+	}
+
+	if err := h.EventHandler.Handle(ctx, event); err != nil {
+		return err
+	}
+
+	if err := h.repository.MarkProcessed(ctx, handlerName, idempotencyKey); err != nil {
+		return fmt.Errorf("could not mark event as processed by %s: %w", handlerName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type processedEventsRepositoryMock struct {
+	processed map[string]bool
+}
+
+func (m *processedEventsRepositoryMock) IsProcessed(ctx context.Context, handlerName string, idempotencyKey string) (bool, error) {
+	return m.processed[handlerName+"/"+idempotencyKey], nil
+}
+
+func (m *processedEventsRepositoryMock) MarkProcessed(ctx context.Context, handlerName string, idempotencyKey string) error {
+	m.processed[handlerName+"/"+idempotencyKey] = true
+	return nil
+}
+
+func TestDeduplicatedHandler(t *testing.T) {
+	ctx := context.Background()
+	repository := &processedEventsRepositoryMock{processed: map[string]bool{}}
+
+	calls := 0
+	failNext := false
+	handler := NewDeduplicatedHandler(
+		cqrs.NewEventHandler("AppendToTracker", func(ctx context.Context, event *entities.TicketBookingConfirmed) error {
+			calls++
+			if failNext {
+				failNext = false
+				return errors.New("failed")
+			}
+			return nil
+		}),
+		repository,
+	)
+
+	assert.Equal(t, "AppendToTracker", handler.HandlerName())
+
+	event := &entities.TicketBookingConfirmed{Header: entities.NewEventHeaderWithIdempotencyKey("key")}
+	redelivered := &entities.TicketBookingConfirmed{Header: entities.NewEventHeaderWithIdempotencyKey("key")}
+
+	require.NoError(t, handler.Handle(ctx, event))
+	require.NoError(t, handler.Handle(ctx, redelivered))
+	assert.Equal(t, 1, calls)
+
+	// failed events are not marked as processed, so they are retried
+	failNext = true
+	other := &entities.TicketBookingConfirmed{Header: entities.NewEventHeaderWithIdempotencyKey("other-key")}
+	require.Error(t, handler.Handle(ctx, other))
+	require.NoError(t, handler.Handle(ctx, other))
+	assert.Equal(t, 3, calls)
+
+	// events without idempotency key are deduplicated by their ID
+	withoutKey := &entities.TicketBookingConfirmed{Header: entities.NewEventHeaderWithIdempotencyKey("")}
+	require.NoError(t, handler.Handle(ctx, withoutKey))
+	require.NoError(t, handler.Handle(ctx, withoutKey))
+	assert.Equal(t, 4, calls)
+}
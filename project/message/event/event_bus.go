@@ -5,14 +5,14 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
-func NewEventBus(pub message.Publisher) *cqrs.EventBus {
+func NewEventBus(pub message.Publisher, marshaler cqrs.CommandEventMarshaler) *cqrs.EventBus {
 	eventBus, err := cqrs.NewEventBusWithConfig(
 		pub,
 		cqrs.EventBusConfig{
 			GeneratePublishTopic: func(params cqrs.GenerateEventPublishTopicParams) (string, error) {
 				return params.EventName, nil
 			},
-			Marshaler: JSONMarshaler,
+			Marshaler: marshaler,
 		})
 
 	if err != nil {
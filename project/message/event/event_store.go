@@ -0,0 +1,80 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// AddEventStoreHandlers stores every event from every event topic in the event store.
+func AddEventStoreHandlers(
+	router *message.Router,
+	subscriber message.Subscriber,
+	repository EventStoreRepository,
+) {
+	for _, topic := range Topics() {
+		router.AddNoPublisherHandler(
+			"EventStore."+topic,
+			topic,
+			subscriber,
+			func(msg *message.Message) error {
+				event, err := storedEventFromMessage(msg)
+				if err != nil {
+					return err
+				}
+
+				return repository.Append(msg.Context(), event)
+			},
+		)
+	}
+}
+
+func storedEventFromMessage(msg *message.Message) (entities.StoredEvent, error) {
+	payload, err := jsonPayload(msg)
+	if err != nil {
+		return entities.StoredEvent{}, err
+	}
+
+	var event struct {
+		Header entities.EventHeader `json:"header"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return entities.StoredEvent{}, fmt.Errorf("could not unmarshal event header of message %s: %w", msg.UUID, err)
+	}
+
+	version, err := MessageVersion(msg)
+	if err != nil {
+		return entities.StoredEvent{}, err
+	}
+
+	return entities.StoredEvent{
+		EventID:       event.Header.ID,
+		EventName:     JSONMarshaler.NameFromMessage(msg),
+		Version:       version,
+		CorrelationID: msg.Metadata.Get("correlation_id"),
+		PublishedAt:   event.Header.PublishedAt,
+		Payload:       json.RawMessage(payload),
+	}, nil
+}
+
+// jsonPayload returns the payload of the message as JSON, so the event store has the same format
+// regardless of the format the event was published in.
+func jsonPayload(msg *message.Message) ([]byte, error) {
+	if msg.Metadata.Get(ContentTypeMetadataKey) != ContentTypeProtobuf {
+		return msg.Payload, nil
+	}
+
+	event, err := newEvent(JSONMarshaler.NameFromMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := (ProtobufMarshaler{}).Unmarshal(msg, event); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(event)
+}
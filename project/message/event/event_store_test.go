@@ -0,0 +1,40 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredEventFromMessage(t *testing.T) {
+	event := entities.TicketBookingConfirmed{
+		Header:        entities.NewEventHeader(),
+		TicketID:      "ticket-id",
+		CustomerEmail: "customer@example.com",
+		Price:         entities.Price{Amount: entities.MustNewDecimal("10.00"), Currency: "EUR"},
+	}
+
+	msg, err := JSONMarshaler.Marshal(event)
+	require.NoError(t, err)
+	msg.Metadata.Set("correlation_id", "correlation-id")
+	msg.SetContext(context.Background())
+
+	stored, err := storedEventFromMessage(msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, event.Header.ID, stored.EventID)
+	assert.Equal(t, "TicketBookingConfirmed", stored.EventName)
+	assert.Equal(t, "correlation-id", stored.CorrelationID)
+	assert.True(t, event.Header.PublishedAt.Equal(stored.PublishedAt))
+	assert.Equal(t, 1, stored.Version)
+	assert.JSONEq(t, string(msg.Payload), string(stored.Payload))
+}
+
+func TestTopics(t *testing.T) {
+	assert.Contains(t, Topics(), "BookingMade")
+	assert.Contains(t, Topics(), "TicketReceiptIssued")
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+
+	"tickets/entities"
+)
+
+// allEvents lists every event published through the event bus.
+// When adding a new event, add it here so it's recorded in the event store.
+var allEvents = []any{
+	entities.TicketBookingConfirmed{},
+	entities.TicketBookingCanceled{},
+	entities.TicketRefunded{},
+	entities.BookingMade{},
+	entities.TicketPrinted{},
+	entities.TicketReceiptIssued{},
+}
+
+// eventSchemas has the current version of every event, with upcasters from its previous versions.
+// When an event changes in a backward incompatible way (for example a field is renamed), bump its version,
+// add an upcaster from the previous version, and add a payload of the previous version to testdata.
+var eventSchemas = EventSchemas{
+	"TicketBookingConfirmed": {Version: 1},
+	"TicketBookingCanceled":  {Version: 1},
+	"TicketRefunded":         {Version: 1},
+	"BookingMade":            {Version: 1},
+	"TicketPrinted":          {Version: 1},
+	"TicketReceiptIssued":    {Version: 1},
+}
+
+// Topics returns the topics of all events published through the event bus.
+func Topics() []string {
+	topics := make([]string, 0, len(allEvents))
+	for _, event := range allEvents {
+		topics = append(topics, JSONMarshaler.Name(event))
+	}
+
+	return topics
+}
+
+// newEvent returns a pointer to a new event with the given name.
+func newEvent(eventName string) (any, error) {
+	for _, event := range allEvents {
+		if JSONMarshaler.Name(event) == eventName {
+			return reflect.New(reflect.TypeOf(event)).Interface(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown event %s", eventName)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"tickets/entities"
+
+	"github.com/google/uuid"
 )
 
 type SpreadsheetsAPI interface {
@@ -14,9 +16,40 @@ type ReceiptsService interface {
 	IssueReceipt(ctx context.Context, request entities.IssueReceiptRequest) (entities.IssueReceiptResponse, error)
 }
 
+type DeadNationAPI interface {
+	BookInDeadNation(ctx context.Context, request entities.DeadNationBooking) error
+}
+
 type TicketsRepository interface {
 	Save(ctx context.Context, ticket *entities.Ticket) error
-	Delete(ctx context.Context, ticketID string) error
+	Cancel(ctx context.Context, ticket *entities.Ticket) error
+	UpdateStatus(ctx context.Context, ticketID string, status entities.TicketStatus) error
+	MarkPrinted(ctx context.Context, ticketID string, fileName string) error
+}
+
+type ReceiptsRepository interface {
+	Save(ctx context.Context, receipt entities.Receipt) error
+}
+
+type OpsBookingsRepository interface {
+	OnBookingMade(ctx context.Context, event *entities.BookingMade) error
+	OnTicketBookingConfirmed(ctx context.Context, event *entities.TicketBookingConfirmed) error
+	OnTicketBookingCanceled(ctx context.Context, event *entities.TicketBookingCanceled) error
+	OnTicketPrinted(ctx context.Context, event *entities.TicketPrinted) error
+	OnTicketRefunded(ctx context.Context, event *entities.TicketRefunded) error
+}
+
+type ProcessedEventsRepository interface {
+	IsProcessed(ctx context.Context, handlerName string, idempotencyKey string) (bool, error)
+	MarkProcessed(ctx context.Context, handlerName string, idempotencyKey string) error
+}
+
+type EventStoreRepository interface {
+	Append(ctx context.Context, event entities.StoredEvent) error
+}
+
+type ShowsRepository interface {
+	ByID(ctx context.Context, id uuid.UUID) (entities.ShowWithAvailability, error)
 }
 
 type FilesAPI interface {
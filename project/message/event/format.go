@@ -0,0 +1,73 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// ContentTypeMetadataKey is the metadata key with the encoding of the payload.
+// Messages published before protobuf was supported don't have it, they are JSON.
+const ContentTypeMetadataKey = "content_type"
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Format is the encoding used for published events.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatProtobuf Format = "protobuf"
+)
+
+// ParseFormat parses the events format from configuration, empty means JSON.
+func ParseFormat(v string) (Format, error) {
+	switch Format(v) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatProtobuf:
+		return FormatProtobuf, nil
+	default:
+		return "", fmt.Errorf("unknown events format %q, expected %q or %q", v, FormatJSON, FormatProtobuf)
+	}
+}
+
+// Marshaler publishes events in the configured format, and unmarshals events in any format
+// based on the content type, so the format can be switched without draining the topics first.
+type Marshaler struct {
+	format Format
+}
+
+func NewMarshaler(format Format) Marshaler {
+	return Marshaler{format: format}
+}
+
+func (m Marshaler) Marshal(v interface{}) (*message.Message, error) {
+	if m.format == FormatProtobuf {
+		return ProtobufMarshaler{Schemas: eventSchemas}.Marshal(v)
+	}
+
+	return JSONMarshaler.Marshal(v)
+}
+
+func (m Marshaler) Unmarshal(msg *message.Message, v interface{}) error {
+	switch contentType := msg.Metadata.Get(ContentTypeMetadataKey); contentType {
+	case "", ContentTypeJSON:
+		return JSONMarshaler.Unmarshal(msg, v)
+	case ContentTypeProtobuf:
+		return ProtobufMarshaler{Schemas: eventSchemas}.Unmarshal(msg, v)
+	default:
+		return fmt.Errorf("unsupported content type %q of message %s", contentType, msg.UUID)
+	}
+}
+
+func (m Marshaler) Name(v interface{}) string {
+	return JSONMarshaler.Name(v)
+}
+
+func (m Marshaler) NameFromMessage(msg *message.Message) string {
+	return JSONMarshaler.NameFromMessage(msg)
+}
@@ -3,24 +3,38 @@ package event
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"tickets/entities"
+	ticketsMessage "tickets/message"
 
 	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 )
 
 type IssueReceiptHandler struct {
-	service ReceiptsService
+	service  ReceiptsService
+	eventBus *cqrs.EventBus
 }
 
-func NewIssueReceiptHandler(service ReceiptsService) *IssueReceiptHandler {
-	return &IssueReceiptHandler{service: service}
+func NewIssueReceiptHandler(service ReceiptsService, eventBus *cqrs.EventBus) *IssueReceiptHandler {
+	return &IssueReceiptHandler{service: service, eventBus: eventBus}
 }
 
 func (handler *IssueReceiptHandler) HandlerName() string {
 	return "IssueReceipt"
 }
 
+// Policy gives up on the receipts API quickly, so an outage doesn't block the consumer.
+// Longer outages are left to the delayed retries.
+func (handler *IssueReceiptHandler) Policy(defaults ticketsMessage.HandlerPolicy) ticketsMessage.HandlerPolicy {
+	defaults.MaxRetries = 1
+	defaults.InitialInterval = 500 * time.Millisecond
+	defaults.AttemptTimeout = 3 * time.Second
+	defaults.Deadline = 5 * time.Second
+	return defaults
+}
+
 func (handler *IssueReceiptHandler) NewEvent() interface{} {
 	return &entities.TicketBookingConfirmed{}
 }
@@ -30,17 +44,28 @@ func (handler *IssueReceiptHandler) Handle(ctx context.Context, event any) error
 	if !ok {
 		return fmt.Errorf("unexpected event type: %T", event)
 	}
-	log.FromContext(ctx).Info("Issuing receipt: '%s'", ticketBooking.TicketID)
+	log.FromContext(ctx).Infof("Issuing receipt: '%s'", ticketBooking.TicketID)
 
 	request := entities.IssueReceiptRequest{
-		TicketID: ticketBooking.TicketID,
-		Price:    ticketBooking.Price,
+		IdempotencyKey: ticketBooking.Header.IdempotencyKey,
+		TicketID:       ticketBooking.TicketID,
+		Price:          ticketBooking.Price,
 	}
 
-	_, err := handler.service.IssueReceipt(ctx, request)
+	response, err := handler.service.IssueReceipt(ctx, request)
 	if err != nil {
 		return fmt.Errorf("failed to issue receipt: %w", err)
 	}
 
+	err = handler.eventBus.Publish(ctx, entities.TicketReceiptIssued{
+		Header:        entities.NewEventHeaderWithIdempotencyKey(ticketBooking.Header.IdempotencyKey),
+		TicketID:      ticketBooking.TicketID,
+		ReceiptNumber: response.ReceiptNumber,
+		IssuedAt:      response.IssuedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish ticket receipt issued event: %w", err)
+	}
+
 	return nil
 }
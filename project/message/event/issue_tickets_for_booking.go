@@ -0,0 +1,61 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/google/uuid"
+)
+
+type IssueTicketsForBookingHandler struct {
+	showsRepository ShowsRepository
+	eventBus        *cqrs.EventBus
+}
+
+func NewIssueTicketsForBookingHandler(showsRepository ShowsRepository, eventBus *cqrs.EventBus) *IssueTicketsForBookingHandler {
+	return &IssueTicketsForBookingHandler{showsRepository: showsRepository, eventBus: eventBus}
+}
+
+func (handler *IssueTicketsForBookingHandler) HandlerName() string {
+	return "IssueTicketsForBooking"
+}
+
+func (handler *IssueTicketsForBookingHandler) NewEvent() interface{} {
+	return &entities.BookingMade{}
+}
+
+func (handler *IssueTicketsForBookingHandler) Handle(ctx context.Context, event any) error {
+	bookingMade, ok := event.(*entities.BookingMade)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+	log.FromContext(ctx).Infof("Issuing %d tickets for booking: '%s'", bookingMade.NumberOfTickets, bookingMade.BookingID)
+
+	show, err := handler.showsRepository.ByID(ctx, bookingMade.ShowId)
+	if err != nil {
+		return fmt.Errorf("failed to get show %s: %w", bookingMade.ShowId, err)
+	}
+
+	for i := 0; i < bookingMade.NumberOfTickets; i++ {
+		// ticket IDs are derived from the booking, so redelivered events issue the same tickets
+		ticketID := uuid.NewSHA1(bookingMade.BookingID, []byte(strconv.Itoa(i))).String()
+
+		err := handler.eventBus.Publish(ctx, entities.TicketBookingConfirmed{
+			Header:        entities.NewEventHeaderWithIdempotencyKey(bookingMade.Header.IdempotencyKey + ticketID),
+			TicketID:      ticketID,
+			CustomerEmail: bookingMade.CustomerEmail,
+			Price:         show.TicketPrice,
+			BookingID:     bookingMade.BookingID.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish ticket booking confirmed event: %w", err)
+		}
+	}
+
+	return nil
+}
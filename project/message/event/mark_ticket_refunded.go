@@ -0,0 +1,45 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+type MarkTicketRefundedHandler struct {
+	repository TicketsRepository
+}
+
+func NewMarkTicketRefundedHandler(repository TicketsRepository) *MarkTicketRefundedHandler {
+	return &MarkTicketRefundedHandler{repository: repository}
+}
+
+func (handler *MarkTicketRefundedHandler) HandlerName() string {
+	return "MarkTicketRefunded"
+}
+
+func (handler *MarkTicketRefundedHandler) NewEvent() interface{} {
+	return &entities.TicketRefunded{}
+}
+
+func (handler *MarkTicketRefundedHandler) Handle(ctx context.Context, event any) error {
+	log.FromContext(ctx).Info("Marking ticket as refunded")
+
+	ticketRefunded, ok := event.(*entities.TicketRefunded)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+
+	err := handler.repository.UpdateStatus(ctx, ticketRefunded.TicketID, entities.TicketStatusRefunded)
+	if errors.Is(err, entities.ErrInvalidTicketStatusTransition) {
+		// retrying won't help, the ticket has already moved past this state
+		log.FromContext(ctx).WithError(err).Warn("Ignoring ticket refund")
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package event
+
+import (
+	"context"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+)
+
+// NewOpsBookingsProjectionHandlers returns handlers building the ops bookings read model.
+// Each event type has its own handler, so the read model keeps up with every stream separately.
+func NewOpsBookingsProjectionHandlers(repository OpsBookingsRepository) []cqrs.EventHandler {
+	return []cqrs.EventHandler{
+		cqrs.NewEventHandler(
+			"OpsBookings.BookingMade",
+			repository.OnBookingMade,
+		),
+		cqrs.NewEventHandler(
+			"OpsBookings.TicketBookingConfirmed",
+			func(ctx context.Context, event *entities.TicketBookingConfirmed) error {
+				if event.BookingID == "" {
+					log.FromContext(ctx).Infof("Ticket %s has no booking, skipping ops read model", event.TicketID)
+					return nil
+				}
+
+				return repository.OnTicketBookingConfirmed(ctx, event)
+			},
+		),
+		cqrs.NewEventHandler(
+			"OpsBookings.TicketBookingCanceled",
+			repository.OnTicketBookingCanceled,
+		),
+		cqrs.NewEventHandler(
+			"OpsBookings.TicketPrinted",
+			repository.OnTicketPrinted,
+		),
+		cqrs.NewEventHandler(
+			"OpsBookings.TicketRefunded",
+			repository.OnTicketRefunded,
+		),
+	}
+}
@@ -1,32 +1,78 @@
 package event
 
 import (
+	ticketsMessage "tickets/message"
+
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
-func RegisterEventHandlers(
-	router *message.Router,
-	config cqrs.EventProcessorConfig,
+// replaySafeHandlers only write to our own database, so replaying events to them
+// doesn't call external services or publish new events.
+var replaySafeHandlers = map[string]bool{
+	"SaveToDatabase":                     true,
+	"CancelTicketInDatabase":             true,
+	"MarkTicketRefunded":                 true,
+	"StoreTicketFileName":                true,
+	"StoreReceipt":                       true,
+	"OpsBookings.BookingMade":            true,
+	"OpsBookings.TicketBookingConfirmed": true,
+	"OpsBookings.TicketBookingCanceled":  true,
+	"OpsBookings.TicketPrinted":          true,
+	"OpsBookings.TicketRefunded":         true,
+}
+
+func IsReplaySafe(handlerName string) bool {
+	return replaySafeHandlers[handlerName]
+}
+
+func NewEventHandlers(
 	spreadsheetsService SpreadsheetsAPI,
 	receiptsService ReceiptsService,
+	deadNationAPI DeadNationAPI,
 	repository TicketsRepository,
+	showsRepository ShowsRepository,
+	receiptsRepository ReceiptsRepository,
+	opsBookingsRepository OpsBookingsRepository,
 	filesService FilesAPI,
 	eventBus *cqrs.EventBus,
+) []cqrs.EventHandler {
+	handlers := []cqrs.EventHandler{
+		NewAppendToTrackerHandler(spreadsheetsService),
+		NewCancelTicketHandler(spreadsheetsService),
+		NewIssueReceiptHandler(receiptsService, eventBus),
+		NewStoreReceiptHandler(receiptsRepository),
+		NewSaveToDatabaseHandler(repository),
+		NewCancelTicketInDatabaseHandler(repository),
+		NewMarkTicketRefundedHandler(repository),
+		NewStoreTicketFileNameHandler(repository),
+		NewSaveToFileHandler(filesService, eventBus),
+		NewIssueTicketsForBookingHandler(showsRepository, eventBus),
+		NewBookPlacesInDeadNationHandler(showsRepository, deadNationAPI),
+	}
+
+	return append(handlers, NewOpsBookingsProjectionHandlers(opsBookingsRepository)...)
+}
+
+func RegisterEventHandlers(
+	router *message.Router,
+	config cqrs.EventProcessorConfig,
+	handlers []cqrs.EventHandler,
+	processedEventsRepository ProcessedEventsRepository,
+	handlerPolicies *ticketsMessage.HandlerPolicies,
 ) *cqrs.EventProcessor {
 	eventProcessor, err := cqrs.NewEventProcessorWithConfig(router, config)
 	if err != nil {
 		panic(err)
 	}
 
-	err = eventProcessor.AddHandlers(
-		NewAppendToTrackerHandler(spreadsheetsService),
-		NewCancelTicketHandler(spreadsheetsService),
-		NewIssueReceiptHandler(receiptsService),
-		NewSaveToDatabaseHandler(repository),
-		NewDeleteCanceledTicketsHandler(repository),
-		NewSaveToFileHandler(filesService, eventBus),
-	)
+	deduplicatedHandlers := make([]cqrs.EventHandler, 0, len(handlers))
+	for _, handler := range handlers {
+		handlerPolicies.Register(handler)
+		deduplicatedHandlers = append(deduplicatedHandlers, NewDeduplicatedHandler(handler, processedEventsRepository))
+	}
+
+	err = eventProcessor.AddHandlers(deduplicatedHandlers...)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// ProtobufMarshaler marshals events to protobuf messages defined in entities/events.proto.
+//
+// Protobuf payloads evolve by adding fields with new numbers, so they are not upcasted,
+// but they are marked with the version of the event like JSON payloads.
+type ProtobufMarshaler struct {
+	Schemas EventSchemas
+}
+
+func (m ProtobufMarshaler) Marshal(v interface{}) (*message.Message, error) {
+	payload, err := marshalProtoEvent(v)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.Metadata.Set("name", m.Name(v))
+	msg.Metadata.Set(VersionMetadataKey, strconv.Itoa(m.Schemas.CurrentVersion(m.Name(v))))
+	msg.Metadata.Set(ContentTypeMetadataKey, ContentTypeProtobuf)
+
+	return msg, nil
+}
+
+func (m ProtobufMarshaler) Unmarshal(msg *message.Message, v interface{}) error {
+	if err := unmarshalProtoEvent(msg.Payload, v); err != nil {
+		return fmt.Errorf("could not unmarshal protobuf payload of message %s: %w", msg.UUID, err)
+	}
+
+	return nil
+}
+
+func (m ProtobufMarshaler) Name(v interface{}) string {
+	return cqrs.StructName(v)
+}
+
+func (m ProtobufMarshaler) NameFromMessage(msg *message.Message) string {
+	return msg.Metadata.Get("name")
+}
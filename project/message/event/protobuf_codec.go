@@ -0,0 +1,254 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"tickets/entities"
+	"tickets/entities/eventspb"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Events are mapped to the messages generated from entities/events.proto,
+// so consumers in other languages can decode the payloads with code generated from the same schema.
+
+func marshalProtoEvent(v any) ([]byte, error) {
+	var msg proto.Message
+
+	switch event := v.(type) {
+	case *entities.TicketBookingConfirmed:
+		return marshalProtoEvent(*event)
+	case entities.TicketBookingConfirmed:
+		msg = &eventspb.TicketBookingConfirmed{
+			Header:        toProtoHeader(event.Header),
+			TicketId:      event.TicketID,
+			CustomerEmail: event.CustomerEmail,
+			Price:         toProtoPrice(event.Price),
+			BookingId:     event.BookingID,
+		}
+
+	case *entities.TicketBookingCanceled:
+		return marshalProtoEvent(*event)
+	case entities.TicketBookingCanceled:
+		msg = &eventspb.TicketBookingCanceled{
+			Header:        toProtoHeader(event.Header),
+			TicketId:      event.TicketID,
+			CustomerEmail: event.CustomerEmail,
+			Price:         toProtoPrice(event.Price),
+		}
+
+	case *entities.TicketRefunded:
+		return marshalProtoEvent(*event)
+	case entities.TicketRefunded:
+		msg = &eventspb.TicketRefunded{
+			Header:   toProtoHeader(event.Header),
+			TicketId: event.TicketID,
+		}
+
+	case *entities.BookingMade:
+		return marshalProtoEvent(*event)
+	case entities.BookingMade:
+		msg = &eventspb.BookingMade{
+			Header:          toProtoHeader(event.Header),
+			NumberOfTickets: int64(event.NumberOfTickets),
+			BookingId:       event.BookingID.String(),
+			CustomerEmail:   event.CustomerEmail,
+			ShowId:          event.ShowId.String(),
+		}
+
+	case *entities.TicketPrinted:
+		return marshalProtoEvent(*event)
+	case entities.TicketPrinted:
+		msg = &eventspb.TicketPrinted{
+			Header:   toProtoHeader(event.Header),
+			TicketId: event.TicketID,
+			FileName: event.FileName,
+		}
+
+	case *entities.TicketReceiptIssued:
+		return marshalProtoEvent(*event)
+	case entities.TicketReceiptIssued:
+		msg = &eventspb.TicketReceiptIssued{
+			Header:        toProtoHeader(event.Header),
+			TicketId:      event.TicketID,
+			ReceiptNumber: event.ReceiptNumber,
+			IssuedAt:      toProtoTimestamp(event.IssuedAt),
+		}
+
+	default:
+		return nil, fmt.Errorf("no protobuf schema for %T", v)
+	}
+
+	return proto.Marshal(msg)
+}
+
+func unmarshalProtoEvent(b []byte, v any) error {
+	switch event := v.(type) {
+	case *entities.TicketBookingConfirmed:
+		var msg eventspb.TicketBookingConfirmed
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		price, err := fromProtoPrice(msg.GetPrice())
+		if err != nil {
+			return err
+		}
+
+		*event = entities.TicketBookingConfirmed{
+			Header:        fromProtoHeader(msg.GetHeader()),
+			TicketID:      msg.GetTicketId(),
+			CustomerEmail: msg.GetCustomerEmail(),
+			Price:         price,
+			BookingID:     msg.GetBookingId(),
+		}
+
+	case *entities.TicketBookingCanceled:
+		var msg eventspb.TicketBookingCanceled
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		price, err := fromProtoPrice(msg.GetPrice())
+		if err != nil {
+			return err
+		}
+
+		*event = entities.TicketBookingCanceled{
+			Header:        fromProtoHeader(msg.GetHeader()),
+			TicketID:      msg.GetTicketId(),
+			CustomerEmail: msg.GetCustomerEmail(),
+			Price:         price,
+		}
+
+	case *entities.TicketRefunded:
+		var msg eventspb.TicketRefunded
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		*event = entities.TicketRefunded{
+			Header:   fromProtoHeader(msg.GetHeader()),
+			TicketID: msg.GetTicketId(),
+		}
+
+	case *entities.BookingMade:
+		var msg eventspb.BookingMade
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		bookingID, err := fromProtoUUID(msg.GetBookingId())
+		if err != nil {
+			return fmt.Errorf("invalid booking_id: %w", err)
+		}
+		showID, err := fromProtoUUID(msg.GetShowId())
+		if err != nil {
+			return fmt.Errorf("invalid show_id: %w", err)
+		}
+
+		*event = entities.BookingMade{
+			Header:          fromProtoHeader(msg.GetHeader()),
+			NumberOfTickets: int(msg.GetNumberOfTickets()),
+			BookingID:       bookingID,
+			CustomerEmail:   msg.GetCustomerEmail(),
+			ShowId:          showID,
+		}
+
+	case *entities.TicketPrinted:
+		var msg eventspb.TicketPrinted
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		*event = entities.TicketPrinted{
+			Header:   fromProtoHeader(msg.GetHeader()),
+			TicketID: msg.GetTicketId(),
+			FileName: msg.GetFileName(),
+		}
+
+	case *entities.TicketReceiptIssued:
+		var msg eventspb.TicketReceiptIssued
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+
+		*event = entities.TicketReceiptIssued{
+			Header:        fromProtoHeader(msg.GetHeader()),
+			TicketID:      msg.GetTicketId(),
+			ReceiptNumber: msg.GetReceiptNumber(),
+			IssuedAt:      fromProtoTimestamp(msg.GetIssuedAt()),
+		}
+
+	default:
+		return fmt.Errorf("no protobuf schema for %T", v)
+	}
+
+	return nil
+}
+
+func toProtoHeader(h entities.EventHeader) *eventspb.EventHeader {
+	return &eventspb.EventHeader{
+		Id:             h.ID,
+		PublishedAt:    toProtoTimestamp(h.PublishedAt),
+		IdempotencyKey: h.IdempotencyKey,
+	}
+}
+
+func fromProtoHeader(h *eventspb.EventHeader) entities.EventHeader {
+	return entities.EventHeader{
+		ID:             h.GetId(),
+		PublishedAt:    fromProtoTimestamp(h.GetPublishedAt()),
+		IdempotencyKey: h.GetIdempotencyKey(),
+	}
+}
+
+func toProtoPrice(p entities.Price) *eventspb.Price {
+	return &eventspb.Price{
+		Amount:   p.Amount.String(),
+		Currency: p.Currency,
+	}
+}
+
+func fromProtoPrice(p *eventspb.Price) (entities.Price, error) {
+	price := entities.Price{Currency: p.GetCurrency()}
+	if p.GetAmount() == "" {
+		return price, nil
+	}
+
+	amount, err := entities.NewDecimal(p.GetAmount())
+	if err != nil {
+		return entities.Price{}, fmt.Errorf("invalid price: %w", err)
+	}
+	price.Amount = amount
+
+	return price, nil
+}
+
+// toProtoTimestamp leaves zero times unset, so they are decoded back as zero times.
+func toProtoTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func fromProtoTimestamp(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.AsTime()
+}
+
+func fromProtoUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+
+	return uuid.Parse(s)
+}
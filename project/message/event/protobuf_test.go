@@ -0,0 +1,110 @@
+package event
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"tickets/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// TestMarshaler_formats checks that every event can be published in both formats,
+// and that it's decoded to the same event regardless of the format of the consumer.
+func TestMarshaler_formats(t *testing.T) {
+	for _, event := range allEvents {
+		eventName := JSONMarshaler.Name(event)
+
+		expected := reflect.New(reflect.TypeOf(event)).Interface()
+		require.NoError(t, json.Unmarshal(readEventTestdata(t, eventName, eventSchemas.CurrentVersion(eventName)), expected))
+
+		for _, publishFormat := range []Format{FormatJSON, FormatProtobuf} {
+			for _, consumeFormat := range []Format{FormatJSON, FormatProtobuf} {
+				t.Run(eventName+"_"+string(publishFormat)+"_to_"+string(consumeFormat), func(t *testing.T) {
+					msg, err := NewMarshaler(publishFormat).Marshal(expected)
+					require.NoError(t, err)
+
+					consumer := NewMarshaler(consumeFormat)
+					assert.Equal(t, eventName, consumer.NameFromMessage(msg))
+
+					unmarshaled := reflect.New(reflect.TypeOf(event)).Interface()
+					require.NoError(t, consumer.Unmarshal(msg, unmarshaled))
+
+					assert.Equal(t, expected, unmarshaled)
+				})
+			}
+		}
+	}
+}
+
+// TestProtobufMarshaler_json_names checks that the JSON payloads use the field names from entities/events.proto,
+// so both formats describe the same events.
+func TestProtobufMarshaler_json_names(t *testing.T) {
+	for _, event := range allEvents {
+		eventName := JSONMarshaler.Name(event)
+
+		t.Run(eventName, func(t *testing.T) {
+			messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName("tickets.events." + eventName))
+			require.NoError(t, err, "event %s is missing in events.proto", eventName)
+
+			payload := readEventTestdata(t, eventName, eventSchemas.CurrentVersion(eventName))
+
+			fromJSON := messageType.New().Interface()
+			require.NoError(t, protojson.Unmarshal(payload, fromJSON))
+
+			expected := reflect.New(reflect.TypeOf(event)).Interface()
+			require.NoError(t, json.Unmarshal(payload, expected))
+
+			msg, err := NewMarshaler(FormatProtobuf).Marshal(expected)
+			require.NoError(t, err)
+
+			fromMarshaler := messageType.New().Interface()
+			require.NoError(t, proto.Unmarshal(msg.Payload, fromMarshaler))
+			assert.True(
+				t,
+				proto.Equal(fromJSON, fromMarshaler),
+				"protobuf payload doesn't match the JSON one\nexpected: %v\nactual:   %v",
+				fromJSON,
+				fromMarshaler,
+			)
+		})
+	}
+}
+
+func TestMarshaler_without_content_type(t *testing.T) {
+	msg, err := JSONMarshaler.Marshal(readTicketRefunded(t))
+	require.NoError(t, err)
+	delete(msg.Metadata, ContentTypeMetadataKey)
+
+	var event entities.TicketRefunded
+	require.NoError(t, NewMarshaler(FormatProtobuf).Unmarshal(msg, &event))
+	assert.Equal(t, readTicketRefunded(t), event)
+}
+
+func TestStoredEventFromMessage_protobuf(t *testing.T) {
+	event := readTicketRefunded(t)
+
+	msg, err := NewMarshaler(FormatProtobuf).Marshal(event)
+	require.NoError(t, err)
+
+	stored, err := storedEventFromMessage(msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, event.Header.ID, stored.EventID)
+	assert.JSONEq(t, string(readEventTestdata(t, "TicketRefunded", 1)), string(stored.Payload))
+}
+
+func readTicketRefunded(t *testing.T) entities.TicketRefunded {
+	t.Helper()
+
+	var event entities.TicketRefunded
+	require.NoError(t, json.Unmarshal(readEventTestdata(t, "TicketRefunded", 1), &event))
+
+	return event
+}
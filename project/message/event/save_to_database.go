@@ -3,8 +3,10 @@ package event
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"tickets/entities"
+	ticketsMessage "tickets/message"
 
 	"github.com/ThreeDotsLabs/go-event-driven/common/log"
 )
@@ -21,6 +23,14 @@ func (handler *SaveToDatabaseHandler) HandlerName() string {
 	return "SaveToDatabase"
 }
 
+// Policy makes saving fail fast, a database which doesn't respond in a few seconds won't respond after more retries.
+func (handler *SaveToDatabaseHandler) Policy(defaults ticketsMessage.HandlerPolicy) ticketsMessage.HandlerPolicy {
+	defaults.MaxRetries = 3
+	defaults.AttemptTimeout = 5 * time.Second
+	defaults.Deadline = 20 * time.Second
+	return defaults
+}
+
 func (handler *SaveToDatabaseHandler) NewEvent() interface{} {
 	return &entities.TicketBookingConfirmed{}
 }
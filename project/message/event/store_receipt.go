@@ -0,0 +1,41 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+type StoreReceiptHandler struct {
+	repository ReceiptsRepository
+}
+
+func NewStoreReceiptHandler(repository ReceiptsRepository) *StoreReceiptHandler {
+	return &StoreReceiptHandler{repository: repository}
+}
+
+func (handler *StoreReceiptHandler) HandlerName() string {
+	return "StoreReceipt"
+}
+
+func (handler *StoreReceiptHandler) NewEvent() interface{} {
+	return &entities.TicketReceiptIssued{}
+}
+
+func (handler *StoreReceiptHandler) Handle(ctx context.Context, event any) error {
+	log.FromContext(ctx).Info("Storing receipt")
+
+	receiptIssued, ok := event.(*entities.TicketReceiptIssued)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+
+	return handler.repository.Save(ctx, entities.Receipt{
+		TicketID:      receiptIssued.TicketID,
+		ReceiptNumber: receiptIssued.ReceiptNumber,
+		IssuedAt:      receiptIssued.IssuedAt,
+	})
+}
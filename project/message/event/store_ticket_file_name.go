@@ -0,0 +1,38 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+type StoreTicketFileNameHandler struct {
+	repository TicketsRepository
+}
+
+func NewStoreTicketFileNameHandler(repository TicketsRepository) *StoreTicketFileNameHandler {
+	return &StoreTicketFileNameHandler{repository: repository}
+}
+
+func (handler *StoreTicketFileNameHandler) HandlerName() string {
+	return "StoreTicketFileName"
+}
+
+func (handler *StoreTicketFileNameHandler) NewEvent() interface{} {
+	return &entities.TicketPrinted{}
+}
+
+func (handler *StoreTicketFileNameHandler) Handle(ctx context.Context, event any) error {
+	log.FromContext(ctx).Info("Storing ticket file name")
+
+	ticketPrinted, ok := event.(*entities.TicketPrinted)
+	if !ok {
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+
+	// TicketPrinted may arrive before the ticket is saved, the error makes the message redelivered later
+	return handler.repository.MarkPrinted(ctx, ticketPrinted.TicketID, ticketPrinted.FileName)
+}
@@ -30,7 +30,7 @@ func (handler *CancelTicketHandler) Handle(ctx context.Context, event any) error
 	if !ok {
 		return fmt.Errorf("unexpected event type: %T", event)
 	}
-	log.FromContext(ctx).Info("Issuing receipt: '%s'", ticketBooking.TicketID)
+	log.FromContext(ctx).Infof("Issuing receipt: '%s'", ticketBooking.TicketID)
 
 	return handler.service.AppendRow(
 		ctx,
@@ -38,7 +38,7 @@ func (handler *CancelTicketHandler) Handle(ctx context.Context, event any) error
 		[]string{
 			ticketBooking.TicketID,
 			ticketBooking.CustomerEmail,
-			ticketBooking.Price.Amount,
+			ticketBooking.Price.Amount.String(),
 			ticketBooking.Price.Currency,
 		},
 	)
@@ -46,7 +46,7 @@ func (handler *SaveToFileHandler) Handle(ctx context.Context, event any) error {
 	</head>
 	<body>
 		<h1>Ticket - ` + ticketBooking.TicketID + `</h1>
-		<h1>Price:` + ticketBooking.Price.Amount + ` ` + ticketBooking.Price.Currency + `</h1>
+		<h1>Price:` + ticketBooking.Price.Amount.String() + ` ` + ticketBooking.Price.Currency + `</h1>
 	</body>
 	</html>
 `
@@ -58,7 +58,7 @@ func (handler *SaveToFileHandler) Handle(ctx context.Context, event any) error {
 	}
 
 	err = handler.eventBus.Publish(ctx, entities.TicketPrinted{
-		Header:   entities.NewEventHeader(),
+		Header:   entities.NewEventHeaderWithIdempotencyKey(ticketBooking.Header.IdempotencyKey),
 		TicketID: ticketBooking.TicketID,
 		FileName: fileName,
 	})
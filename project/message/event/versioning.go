@@ -0,0 +1,125 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// VersionMetadataKey is the metadata key with the schema version of the event in the payload.
+// Messages published before events were versioned don't have it, they are version 1.
+const VersionMetadataKey = "version"
+
+// Upcaster transforms the payload of an event from one version to the next one.
+type Upcaster func(payload map[string]any) (map[string]any, error)
+
+// EventSchema is the current version of an event, with upcasters from all previous versions.
+// Upcasters[v] transforms version v to version v+1.
+type EventSchema struct {
+	Version   int
+	Upcasters map[int]Upcaster
+}
+
+type EventSchemas map[string]EventSchema
+
+// Validate checks that every historic version of every event can be upcasted to the current one.
+func (s EventSchemas) Validate() error {
+	for eventName, schema := range s {
+		if schema.Version < 1 {
+			return fmt.Errorf("event %s has invalid version %d", eventName, schema.Version)
+		}
+
+		for version := 1; version < schema.Version; version++ {
+			if schema.Upcasters[version] == nil {
+				return fmt.Errorf("event %s has no upcaster from version %d", eventName, version)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s EventSchemas) CurrentVersion(eventName string) int {
+	if schema, ok := s[eventName]; ok {
+		return schema.Version
+	}
+
+	return 1
+}
+
+// Upcast transforms the payload of an event in the given version to the current version.
+func (s EventSchemas) Upcast(eventName string, version int, payload []byte) ([]byte, error) {
+	current := s.CurrentVersion(eventName)
+	if version == current {
+		return payload, nil
+	}
+	if version < 1 || version > current {
+		return nil, fmt.Errorf("unsupported version %d of event %s, current version is %d", version, eventName, current)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("could not unmarshal version %d of event %s: %w", version, eventName, err)
+	}
+
+	for ; version < current; version++ {
+		var err error
+		fields, err = s[eventName].Upcasters[version](fields)
+		if err != nil {
+			return nil, fmt.Errorf("could not upcast event %s from version %d: %w", eventName, version, err)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// VersionedMarshaler marks marshaled events with their version,
+// and upcasts events in older versions before they are unmarshaled.
+type VersionedMarshaler struct {
+	cqrs.JSONMarshaler
+	Schemas EventSchemas
+}
+
+func (m VersionedMarshaler) Marshal(v interface{}) (*message.Message, error) {
+	msg, err := m.JSONMarshaler.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Metadata.Set(VersionMetadataKey, strconv.Itoa(m.Schemas.CurrentVersion(m.Name(v))))
+	msg.Metadata.Set(ContentTypeMetadataKey, ContentTypeJSON)
+
+	return msg, nil
+}
+
+func (m VersionedMarshaler) Unmarshal(msg *message.Message, v interface{}) error {
+	version, err := MessageVersion(msg)
+	if err != nil {
+		return err
+	}
+
+	payload, err := m.Schemas.Upcast(m.NameFromMessage(msg), version, msg.Payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+// MessageVersion returns the schema version of the event in the message.
+func MessageVersion(msg *message.Message) (int, error) {
+	v := msg.Metadata.Get(VersionMetadataKey)
+	if v == "" {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q of message %s: %w", v, msg.UUID, err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,105 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSchemas_Validate(t *testing.T) {
+	require.NoError(t, eventSchemas.Validate())
+
+	for _, event := range allEvents {
+		_, ok := eventSchemas[JSONMarshaler.Name(event)]
+		assert.Truef(t, ok, "event %s has no schema", JSONMarshaler.Name(event))
+	}
+}
+
+// TestHistoricEventVersions checks that a payload of every version of every event,
+// stored in testdata/<EventName>.v<version>.json, is unmarshaled to the same event as the current version.
+func TestHistoricEventVersions(t *testing.T) {
+	for _, event := range allEvents {
+		eventName := JSONMarshaler.Name(event)
+		currentVersion := eventSchemas.CurrentVersion(eventName)
+
+		expected := reflect.New(reflect.TypeOf(event)).Interface()
+		require.NoError(t, json.Unmarshal(readEventTestdata(t, eventName, currentVersion), expected))
+
+		for version := 1; version <= currentVersion; version++ {
+			t.Run(fmt.Sprintf("%s_v%d", eventName, version), func(t *testing.T) {
+				msg := message.NewMessage(watermill.NewUUID(), readEventTestdata(t, eventName, version))
+				msg.Metadata.Set("name", eventName)
+				msg.Metadata.Set(VersionMetadataKey, strconv.Itoa(version))
+
+				unmarshaled := reflect.New(reflect.TypeOf(event)).Interface()
+				require.NoError(t, JSONMarshaler.Unmarshal(msg, unmarshaled))
+
+				assert.Equal(t, expected, unmarshaled)
+			})
+		}
+	}
+}
+
+func readEventTestdata(t *testing.T, eventName string, version int) []byte {
+	t.Helper()
+
+	payload, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("%s.v%d.json", eventName, version)))
+	require.NoErrorf(t, err, "every version of %s needs a payload in testdata", eventName)
+
+	return payload
+}
+
+func TestVersionedMarshaler(t *testing.T) {
+	type Renamed struct {
+		Email string `json:"email"`
+		Note  string `json:"note"`
+	}
+
+	marshaler := VersionedMarshaler{
+		JSONMarshaler: JSONMarshaler.JSONMarshaler,
+		Schemas: EventSchemas{
+			"Renamed": {
+				Version: 3,
+				Upcasters: map[int]Upcaster{
+					1: func(payload map[string]any) (map[string]any, error) {
+						payload["email"] = payload["customer_email"]
+						delete(payload, "customer_email")
+						return payload, nil
+					},
+					2: func(payload map[string]any) (map[string]any, error) {
+						payload["note"] = "upcasted"
+						return payload, nil
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, marshaler.Schemas.Validate())
+
+	msg, err := marshaler.Marshal(Renamed{Email: "customer@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", msg.Metadata.Get(VersionMetadataKey))
+
+	// messages published before versioning have no version
+	old := message.NewMessage(watermill.NewUUID(), []byte(`{"customer_email":"customer@example.com"}`))
+	old.Metadata.Set("name", "Renamed")
+
+	var event Renamed
+	require.NoError(t, marshaler.Unmarshal(old, &event))
+	assert.Equal(t, Renamed{Email: "customer@example.com", Note: "upcasted"}, event)
+
+	old.Metadata.Set(VersionMetadataKey, "4")
+	assert.Error(t, marshaler.Unmarshal(old, &event))
+
+	delete(marshaler.Schemas["Renamed"].Upcasters, 2)
+	assert.Error(t, marshaler.Schemas.Validate())
+}
@@ -1,26 +1,45 @@
 package message
 
 import (
-	"time"
+	"errors"
+
+	"tickets/message/outbox"
 
 	"github.com/ThreeDotsLabs/go-event-driven/common/log"
-	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/lithammer/shortuuid/v3"
 	"github.com/sirupsen/logrus"
 )
 
-func useMiddlewares(router *message.Router, watermillLogger watermill.LoggerAdapter) {
+func useMiddlewares(router *message.Router, config RouterConfig) {
+	// added first, so messages nacked while shutting down don't get to retries and the poison queue
+	if config.Drain != nil {
+		router.AddMiddleware(config.Drain.Middleware)
+	}
+
+	router.AddMiddleware(retriedMessageFilter)
+
+	// messages aborted by the shutdown are nacked, they aren't broken
+	poisonQueue, err := middleware.PoisonQueueWithFilter(config.PoisonQueuePublisher, PoisonQueueTopic, func(err error) bool {
+		return !errors.Is(err, ErrShuttingDown)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// added before retries, so it gets the error only after all of them;
+	// forwarding from the outbox is retried by its Postgres subscriber, it can't be moved to the broker;
+	// messages failing in the poison queue handler are nacked, poisoning them again would overwrite their original topic
+	router.AddMiddleware(exceptHandlers(poisonQueue, outbox.ForwarderHandlerName, PoisonQueueHandlerName))
+
+	if config.DelayedRetry != nil {
+		router.AddMiddleware(exceptHandlers(config.DelayedRetry.Middleware, outbox.ForwarderHandlerName, PoisonQueueHandlerName))
+	}
+
 	router.AddMiddleware(middleware.Recoverer)
 
-	router.AddMiddleware(middleware.Retry{
-		MaxRetries:      10,
-		InitialInterval: time.Millisecond * 100,
-		MaxInterval:     time.Second,
-		Multiplier:      2,
-		Logger:          watermillLogger,
-	}.Middleware)
+	router.AddMiddleware(config.HandlerPolicies.Middleware)
 
 	router.AddMiddleware(correlationMiddleware)
 	router.AddMiddleware(loggingMiddleware)
@@ -0,0 +1,55 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// Namespace separates environments sharing one Redis and Postgres, for example staging and load tests.
+// It prefixes topics, consumer groups and the outbox topic (and so the outbox tables, which are named after it),
+// and separates messages scheduled for delayed retries.
+// The empty namespace keeps the names unchanged.
+type Namespace string
+
+var validNamespace = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// ParseNamespace validates the namespace, it's a part of topic and table names, so only a few characters are allowed.
+func ParseNamespace(v string) (Namespace, error) {
+	if !validNamespace.MatchString(v) {
+		return "", fmt.Errorf("invalid namespace %q, only letters, digits, '_' and '-' are allowed", v)
+	}
+
+	return Namespace(v), nil
+}
+
+func (n Namespace) Prefix(name string) string {
+	if n == "" {
+		return name
+	}
+
+	return string(n) + "." + name
+}
+
+// NamespacedPublisher publishes to the topics in the namespace.
+type NamespacedPublisher struct {
+	message.Publisher
+	Namespace Namespace
+}
+
+func (p NamespacedPublisher) Publish(topic string, messages ...*message.Message) error {
+	return p.Publisher.Publish(p.Namespace.Prefix(topic), messages...)
+}
+
+// NamespacedSubscriber subscribes to the topics in the namespace.
+// Handlers still see the topic without the namespace, so it doesn't leak to the poison queue or delayed retries.
+type NamespacedSubscriber struct {
+	message.Subscriber
+	Namespace Namespace
+}
+
+func (s NamespacedSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	return s.Subscriber.Subscribe(ctx, s.Namespace.Prefix(topic))
+}
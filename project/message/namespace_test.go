@@ -0,0 +1,48 @@
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespace(t *testing.T) {
+	for _, valid := range []string{"", "staging", "load-test_1"} {
+		namespace, err := ParseNamespace(valid)
+		require.NoError(t, err)
+		assert.Equal(t, Namespace(valid), namespace)
+	}
+
+	for _, invalid := range []string{"staging.eu", "load test", "prod\""} {
+		_, err := ParseNamespace(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNamespace_Prefix(t *testing.T) {
+	assert.Equal(t, "BookingMade", Namespace("").Prefix("BookingMade"))
+	assert.Equal(t, "staging.BookingMade", Namespace("staging").Prefix("BookingMade"))
+}
+
+func TestNamespacedPubSub(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{Persistent: true}, watermill.NopLogger{})
+
+	publisher := NamespacedPublisher{Publisher: pubSub, Namespace: "staging"}
+	require.NoError(t, publisher.Publish("BookingMade", message.NewMessage(watermill.NewUUID(), nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	raw, err := pubSub.Subscribe(ctx, "staging.BookingMade")
+	require.NoError(t, err)
+	(<-raw).Ack()
+
+	namespaced, err := NamespacedSubscriber{Subscriber: pubSub, Namespace: "staging"}.Subscribe(ctx, "BookingMade")
+	require.NoError(t, err)
+	(<-namespaced).Ack()
+}
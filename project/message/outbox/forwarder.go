@@ -1,6 +1,9 @@
 package outbox
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/ThreeDotsLabs/go-event-driven/common/log"
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/components/forwarder"
@@ -8,14 +11,27 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Forwarder is the router handler moving events from the outbox to the broker.
+type Forwarder struct {
+	subscriber *runningSubscriber
+}
+
+// Running reports whether the forwarder is subscribed to the outbox.
+func (f *Forwarder) Running() bool {
+	return f.subscriber.running.Load()
+}
+
 func AddForwarderHandler(
 	postgresSubscriber message.Subscriber,
 	publisher message.Publisher,
 	router *message.Router,
+	outboxTopic string,
 	logger watermill.LoggerAdapter,
-) {
+) *Forwarder {
+	subscriber := &runningSubscriber{Subscriber: postgresSubscriber}
+
 	_, err := forwarder.NewForwarder(
-		postgresSubscriber,
+		subscriber,
 		publisher,
 		logger,
 		forwarder.Config{
@@ -39,4 +55,34 @@ func AddForwarderHandler(
 	if err != nil {
 		panic(err)
 	}
+
+	return &Forwarder{subscriber: subscriber}
+}
+
+// runningSubscriber knows whether its subscription is open.
+// The router doesn't tell if one of its handlers stopped, for example because its subscription was closed.
+type runningSubscriber struct {
+	message.Subscriber
+	running atomic.Bool
+}
+
+func (s *runningSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	messages, err := s.Subscriber.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *message.Message)
+	s.running.Store(true)
+
+	go func() {
+		defer close(out)
+		defer s.running.Store(false)
+
+		for msg := range messages {
+			out <- msg
+		}
+	}()
+
+	return out, nil
 }
@@ -1,3 +1,8 @@
 package outbox
 
-var outboxTopic = "events_to_forward"
+// Topic is the outbox topic, tables of the outbox are named after it.
+// Use a namespaced topic (message.Namespace.Prefix) to keep outboxes of environments sharing a database apart.
+const Topic = "events_to_forward"
+
+// ForwarderHandlerName is the name of the router handler which Watermill's forwarder adds.
+const ForwarderHandlerName = "events_forwarder"
@@ -11,7 +11,7 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
-func NewPublisherForDb(ctx context.Context, db *sql.Tx) (message.Publisher, error) {
+func NewPublisherForDb(ctx context.Context, db *sql.Tx, outboxTopic string) (message.Publisher, error) {
 	var publisher message.Publisher
 
 	logger := log.NewWatermill(log.FromContext(ctx))
@@ -9,11 +9,11 @@ import (
 	watermillSQL "github.com/ThreeDotsLabs/watermill-sql/v2/pkg/sql"
 )
 
-func NewPostgresSubscriber(db *sql.DB, logger watermill.LoggerAdapter) *watermillSQL.Subscriber {
+func NewPostgresSubscriber(db *sql.DB, pollInterval time.Duration, logger watermill.LoggerAdapter) *watermillSQL.Subscriber {
 	sub, err := watermillSQL.NewSubscriber(
 		db,
 		watermillSQL.SubscriberConfig{
-			PollInterval:     time.Millisecond * 100,
+			PollInterval:     pollInterval,
 			InitializeSchema: true,
 			SchemaAdapter:    watermillSQL.DefaultPostgreSQLSchema{},
 			OffsetsAdapter:   watermillSQL.DefaultPostgreSQLOffsetsAdapter{},
@@ -0,0 +1,105 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+)
+
+const (
+	PoisonQueueTopic       = "poison"
+	PoisonQueueHandlerName = "PoisonQueue"
+)
+
+type PoisonedMessageRepository interface {
+	Save(ctx context.Context, msg entities.PoisonedMessage) error
+	ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error)
+	Delete(ctx context.Context, messageUUID string) error
+}
+
+// AddPoisonQueueHandler stores messages from the poison queue, so they can be inspected and requeued.
+func AddPoisonQueueHandler(
+	router *message.Router,
+	subscriber message.Subscriber,
+	repository PoisonedMessageRepository,
+) {
+	router.AddNoPublisherHandler(
+		PoisonQueueHandlerName,
+		PoisonQueueTopic,
+		subscriber,
+		func(msg *message.Message) error {
+			return repository.Save(msg.Context(), poisonedMessageFromMessage(msg))
+		},
+	)
+}
+
+func poisonedMessageFromMessage(msg *message.Message) entities.PoisonedMessage {
+	metadata := make(map[string]string, len(msg.Metadata))
+	for key, value := range msg.Metadata {
+		metadata[key] = value
+	}
+
+	return entities.PoisonedMessage{
+		MessageUUID: msg.UUID,
+		Topic:       msg.Metadata.Get(middleware.PoisonedTopicKey),
+		HandlerName: msg.Metadata.Get(middleware.PoisonedHandlerKey),
+		Reason:      msg.Metadata.Get(middleware.ReasonForPoisonedKey),
+		Payload:     string(msg.Payload),
+		Metadata:    metadata,
+		PoisonedAt:  time.Now().UTC(),
+	}
+}
+
+type PoisonQueue struct {
+	repository PoisonedMessageRepository
+	publisher  message.Publisher
+}
+
+func NewPoisonQueue(repository PoisonedMessageRepository, publisher message.Publisher) *PoisonQueue {
+	if repository == nil {
+		panic("poisoned message repository is required")
+	}
+	if publisher == nil {
+		panic("publisher is required")
+	}
+
+	return &PoisonQueue{
+		repository: repository,
+		publisher:  publisher,
+	}
+}
+
+// Requeue publishes the poisoned message back to its original topic.
+// Every consumer group of the topic gets it again, so handlers which already processed it rely on deduplication.
+func (q *PoisonQueue) Requeue(ctx context.Context, messageUUID string) error {
+	poisoned, err := q.repository.ByUUID(ctx, messageUUID)
+	if err != nil {
+		return err
+	}
+
+	if poisoned.Topic == "" {
+		return fmt.Errorf("poisoned message %s has no original topic", messageUUID)
+	}
+
+	// the requeued message starts over, with all delayed retries available again
+	msg := message.NewMessage(poisoned.MessageUUID, message.Payload(poisoned.Payload))
+	for key, value := range poisoned.Metadata {
+		switch key {
+		case middleware.ReasonForPoisonedKey, middleware.PoisonedTopicKey, middleware.PoisonedHandlerKey, middleware.PoisonedSubscriberKey,
+			RetryAttemptMetadataKey, RetryHandlerMetadataKey:
+			continue
+		}
+		msg.Metadata.Set(key, value)
+	}
+
+	if err := q.publisher.Publish(poisoned.Topic, msg); err != nil {
+		return fmt.Errorf("could not requeue message %s to %s: %w", messageUUID, poisoned.Topic, err)
+	}
+
+	return q.repository.Delete(ctx, messageUUID)
+}
@@ -0,0 +1,128 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tickets/db/memory"
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingPoisonedMessageRepository struct {
+	lock  sync.Mutex
+	saves int
+}
+
+func (r *failingPoisonedMessageRepository) Save(ctx context.Context, msg entities.PoisonedMessage) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.saves++
+	return errors.New("database is down")
+}
+
+func (r *failingPoisonedMessageRepository) Saves() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.saves
+}
+
+func (r *failingPoisonedMessageRepository) ByUUID(ctx context.Context, messageUUID string) (entities.PoisonedMessage, error) {
+	return entities.PoisonedMessage{}, errors.New("not found")
+}
+
+func (r *failingPoisonedMessageRepository) Delete(ctx context.Context, messageUUID string) error {
+	return nil
+}
+
+func TestPoisonQueueHandler_failingSave(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	scheduler := &messageSchedulerMock{}
+
+	router := NewWatermillRouter(RouterConfig{
+		PoisonQueuePublisher: pubSub,
+		HandlerPolicies:      NewHandlerPolicies(HandlerPolicy{}),
+		DelayedRetry:         NewDelayedRetry(scheduler, []time.Duration{time.Second}),
+	}, watermill.NopLogger{})
+
+	repository := &failingPoisonedMessageRepository{}
+	AddPoisonQueueHandler(router, pubSub, repository)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = router.Run(ctx)
+	}()
+	<-router.Running()
+	defer router.Close()
+
+	poisoned, err := pubSub.Subscribe(ctx, PoisonQueueTopic)
+	require.NoError(t, err)
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte(`{}`))
+	msg.Metadata.Set(middleware.PoisonedTopicKey, "TicketBookingConfirmed")
+	msg.Metadata.Set(middleware.PoisonedHandlerKey, "IssueReceipt")
+	require.NoError(t, pubSub.Publish(PoisonQueueTopic, msg))
+
+	published := <-poisoned
+	published.Ack()
+
+	assert.Eventually(t, func() bool {
+		return repository.Saves() >= 3
+	}, time.Second, time.Millisecond, "message should be redelivered to the poison queue handler")
+
+	select {
+	case msg := <-poisoned:
+		t.Fatalf("message was poisoned again with topic %s", msg.Metadata.Get(middleware.PoisonedTopicKey))
+	default:
+	}
+
+	assert.Empty(t, scheduler.scheduled, "poison queue handler should not be retried with a delay")
+}
+
+func TestPoisonQueue_Requeue(t *testing.T) {
+	ctx := context.Background()
+	pubSub := gochannel.NewGoChannel(gochannel.Config{Persistent: true}, watermill.NopLogger{})
+	repository := memory.NewPoisonedMessageRepository()
+	messageUUID := watermill.NewUUID()
+
+	require.NoError(t, repository.Save(ctx, entities.PoisonedMessage{
+		MessageUUID: messageUUID,
+		Topic:       "TicketBookingConfirmed",
+		HandlerName: "IssueReceipt",
+		Payload:     `{}`,
+		Metadata: map[string]string{
+			"correlation_id":                "correlation-id",
+			RetryAttemptMetadataKey:         "3",
+			RetryHandlerMetadataKey:         "IssueReceipt",
+			middleware.PoisonedTopicKey:     "TicketBookingConfirmed",
+			middleware.ReasonForPoisonedKey: "receipts API is down",
+		},
+	}))
+
+	require.NoError(t, NewPoisonQueue(repository, pubSub).Requeue(ctx, messageUUID))
+
+	messages, err := pubSub.Subscribe(ctx, "TicketBookingConfirmed")
+	require.NoError(t, err)
+	requeued := <-messages
+
+	assert.Equal(t, "correlation-id", requeued.Metadata.Get("correlation_id"))
+	assert.Empty(t, requeued.Metadata.Get(RetryAttemptMetadataKey), "requeued message should get all delayed retries again")
+	assert.Empty(t, requeued.Metadata.Get(RetryHandlerMetadataKey))
+	assert.Empty(t, requeued.Metadata.Get(middleware.PoisonedTopicKey))
+
+	_, err = repository.ByUUID(ctx, messageUUID)
+	assert.ErrorIs(t, err, entities.ErrPoisonedMessageNotFound)
+}
@@ -0,0 +1,146 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// HandlerPolicy says how many times and how long a handler can try to handle a message.
+type HandlerPolicy struct {
+	MaxRetries      int           `yaml:"max_retries"`
+	InitialInterval time.Duration `yaml:"initial_interval"`
+	// MaxInterval caps the backoff between retries, 0 means no cap.
+	MaxInterval time.Duration `yaml:"max_interval"`
+	Multiplier  float64       `yaml:"multiplier"`
+
+	// AttemptTimeout limits a single attempt, 0 means no limit.
+	AttemptTimeout time.Duration `yaml:"attempt_timeout"`
+	// Deadline limits all attempts together, including backoff, 0 means no limit.
+	Deadline time.Duration `yaml:"deadline"`
+}
+
+// DefaultHandlerPolicy only retries short failures in-process, longer ones are left to the delayed retries.
+func DefaultHandlerPolicy() HandlerPolicy {
+	return HandlerPolicy{
+		MaxRetries:      3,
+		InitialInterval: time.Millisecond * 100,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+	}
+}
+
+func (p HandlerPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative")
+	}
+	if p.InitialInterval < 0 || p.MaxInterval < 0 || p.AttemptTimeout < 0 || p.Deadline < 0 {
+		return fmt.Errorf("durations must not be negative")
+	}
+	if p.Multiplier < 0 {
+		return fmt.Errorf("multiplier must not be negative")
+	}
+
+	return nil
+}
+
+func (p HandlerPolicy) handle(h message.HandlerFunc, msg *message.Message) ([]*message.Message, error) {
+	originalCtx := msg.Context()
+	defer msg.SetContext(originalCtx)
+
+	ctx := originalCtx
+	if p.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Deadline)
+		defer cancel()
+	}
+
+	interval := p.InitialInterval
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancelAttempt := ctx, context.CancelFunc(func() {})
+		if p.AttemptTimeout > 0 {
+			attemptCtx, cancelAttempt = context.WithTimeout(ctx, p.AttemptTimeout)
+		}
+
+		msg.SetContext(attemptCtx)
+		events, err := h(msg)
+		cancelAttempt()
+
+		if err == nil {
+			return events, nil
+		}
+		// the service is shutting down, the message will be redelivered
+		if errors.Is(context.Cause(ctx), ErrShuttingDown) {
+			return nil, fmt.Errorf("%w: %w", ErrShuttingDown, err)
+		}
+		if attempt >= p.MaxRetries {
+			return nil, err
+		}
+
+		log.FromContext(originalCtx).WithError(err).Warnf("Handler failed, retrying in %s (attempt %d of %d)", interval, attempt+1, p.MaxRetries)
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), ErrShuttingDown) {
+				return nil, fmt.Errorf("%w: %w", ErrShuttingDown, err)
+			}
+			return nil, fmt.Errorf("handler deadline exceeded after %d attempts: %w", attempt+1, err)
+		case <-time.After(interval):
+		}
+
+		if p.Multiplier > 0 {
+			interval = time.Duration(float64(interval) * p.Multiplier)
+		}
+		if p.MaxInterval > 0 && interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+}
+
+// PolicyHandler is implemented by handlers which need a different policy than the default one.
+type PolicyHandler interface {
+	HandlerName() string
+	Policy(defaults HandlerPolicy) HandlerPolicy
+}
+
+// HandlerPolicies keeps the policies of handlers by their name.
+// Handlers must be registered before the router is running.
+type HandlerPolicies struct {
+	defaults HandlerPolicy
+	policies map[string]HandlerPolicy
+}
+
+func NewHandlerPolicies(defaults HandlerPolicy) *HandlerPolicies {
+	return &HandlerPolicies{
+		defaults: defaults,
+		policies: map[string]HandlerPolicy{},
+	}
+}
+
+// Register stores the handler's own policy, handlers without one use the defaults.
+func (p *HandlerPolicies) Register(handler any) {
+	policyHandler, ok := handler.(PolicyHandler)
+	if !ok {
+		return
+	}
+
+	p.policies[policyHandler.HandlerName()] = policyHandler.Policy(p.defaults)
+}
+
+func (p *HandlerPolicies) For(handlerName string) HandlerPolicy {
+	if policy, ok := p.policies[handlerName]; ok {
+		return policy
+	}
+
+	return p.defaults
+}
+
+func (p *HandlerPolicies) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		return p.For(message.HandlerNameFromCtx(msg.Context())).handle(h, msg)
+	}
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowHandler struct{}
+
+func (slowHandler) HandlerName() string {
+	return "Slow"
+}
+
+func (slowHandler) Policy(defaults HandlerPolicy) HandlerPolicy {
+	defaults.MaxRetries = 1
+	defaults.AttemptTimeout = 10 * time.Millisecond
+	return defaults
+}
+
+func TestHandlerPolicies(t *testing.T) {
+	policies := NewHandlerPolicies(HandlerPolicy{MaxRetries: 3, InitialInterval: time.Millisecond})
+	policies.Register(slowHandler{})
+	policies.Register(struct{}{})
+
+	assert.Equal(t, 1, policies.For("Slow").MaxRetries)
+	assert.Equal(t, 10*time.Millisecond, policies.For("Slow").AttemptTimeout)
+	assert.Equal(t, 3, policies.For("Other").MaxRetries)
+}
+
+func TestHandlerPolicy_retries(t *testing.T) {
+	policy := HandlerPolicy{MaxRetries: 2, InitialInterval: time.Millisecond, Multiplier: 2}
+
+	attempts := 0
+	_, err := policy.handle(func(msg *message.Message) ([]*message.Message, error) {
+		attempts++
+		return nil, errors.New("failed")
+	}, message.NewMessage(watermill.NewUUID(), nil))
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	_, err = policy.handle(func(msg *message.Message) ([]*message.Message, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, errors.New("failed")
+		}
+		return nil, nil
+	}, message.NewMessage(watermill.NewUUID(), nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestHandlerPolicy_timeouts(t *testing.T) {
+	policy := HandlerPolicy{
+		MaxRetries:      100,
+		InitialInterval: 10 * time.Millisecond,
+		AttemptTimeout:  5 * time.Millisecond,
+		Deadline:        50 * time.Millisecond,
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	originalCtx := msg.Context()
+
+	attempts := 0
+	start := time.Now()
+	_, err := policy.handle(func(msg *message.Message) ([]*message.Message, error) {
+		attempts++
+		<-msg.Context().Done()
+		return nil, msg.Context().Err()
+	}, msg)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, attempts, 100)
+	assert.Equal(t, originalCtx, msg.Context())
+}
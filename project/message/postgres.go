@@ -0,0 +1,52 @@
+package message
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	watermillSQL "github.com/ThreeDotsLabs/watermill-sql/v2/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// NewPostgresBroker stores messages in Postgres, a table per topic, so the service can run without Redis.
+// Subscribers poll their topics every pollInterval.
+func NewPostgresBroker(
+	db *sql.DB,
+	namespace Namespace,
+	pollInterval time.Duration,
+	watermillLogger watermill.LoggerAdapter,
+) (*Broker, error) {
+	pub, err := watermillSQL.NewPublisher(
+		db,
+		watermillSQL.PublisherConfig{
+			SchemaAdapter:        watermillSQL.DefaultPostgreSQLSchema{},
+			AutoInitializeSchema: true,
+		},
+		watermillLogger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBroker(
+		pub,
+		func(consumerGroup string) (message.Subscriber, error) {
+			return watermillSQL.NewSubscriber(
+				db,
+				watermillSQL.SubscriberConfig{
+					ConsumerGroup:    consumerGroup,
+					PollInterval:     pollInterval,
+					InitializeSchema: true,
+					SchemaAdapter:    watermillSQL.DefaultPostgreSQLSchema{},
+					OffsetsAdapter:   watermillSQL.DefaultPostgreSQLOffsetsAdapter{},
+				},
+				watermillLogger,
+			)
+		},
+		namespace,
+		// the database is closed by its owner
+		func() error { return nil },
+		db.PingContext,
+	), nil
+}
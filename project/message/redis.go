@@ -1,28 +1,54 @@
 package message
 
 import (
-	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"context"
+
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/redis/go-redis/v9"
 )
 
-func NewRedisPublisher(rdb *redis.Client, watermillLogger watermill.LoggerAdapter) message.Publisher {
-	var pub message.Publisher
+func NewRedisClient(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+}
+
+// NewRedisBroker uses Redis Streams, the client is closed with the broker.
+func NewRedisBroker(rdb *redis.Client, namespace Namespace, watermillLogger watermill.LoggerAdapter) (*Broker, error) {
+	shared := sharedRedisClient{rdb}
+
 	pub, err := redisstream.NewPublisher(redisstream.PublisherConfig{
-		Client: rdb,
+		Client: shared,
 	}, watermillLogger)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	pub = log.CorrelationPublisherDecorator{pub}
 
-	return pub
+	return newBroker(
+		pub,
+		func(consumerGroup string) (message.Subscriber, error) {
+			return redisstream.NewSubscriber(redisstream.SubscriberConfig{
+				Client:        shared,
+				ConsumerGroup: consumerGroup,
+			}, watermillLogger)
+		},
+		namespace,
+		rdb.Close,
+		func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		},
+	), nil
 }
 
-func NewRedisClient(addr string) *redis.Client {
-	return redis.NewClient(&redis.Options{
-		Addr: addr,
-	})
+// sharedRedisClient is used by the publisher and all subscribers of the broker.
+// They close their client when they are closed, so closing is left to the broker,
+// otherwise the first closed subscriber would break the others.
+type sharedRedisClient struct {
+	redis.UniversalClient
+}
+
+func (c sharedRedisClient) Close() error {
+	return nil
 }
@@ -0,0 +1,69 @@
+package replay
+
+import (
+	"fmt"
+
+	ticketsMessage "tickets/message"
+	"tickets/message/event"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// Topic is the topic of events replayed to a temporary consumer group.
+func Topic(consumerGroup string, eventName string) string {
+	return "replay." + consumerGroup + "." + eventName
+}
+
+// consumerGroupHandler consumes events replayed to a temporary consumer group.
+// It has its own name, so it can run next to the same handler consuming live events.
+type consumerGroupHandler struct {
+	cqrs.EventHandler
+	consumerGroup string
+}
+
+func (h consumerGroupHandler) HandlerName() string {
+	return "replay." + h.consumerGroup + "." + h.EventHandler.HandlerName()
+}
+
+// RegisterConsumerGroupHandlers subscribes the replay-safe handlers to the events replayed to the consumer group,
+// so a service started with the consumer group can rebuild its state while the other instances handle live events.
+//
+// Replayed events are not deduplicated, as they have already been processed when they were published.
+func RegisterConsumerGroupHandlers(
+	router *message.Router,
+	broker *ticketsMessage.Broker,
+	consumerGroupPrefix string,
+	consumerGroup string,
+	handlers []cqrs.EventHandler,
+	marshaler cqrs.CommandEventMarshaler,
+	logger watermill.LoggerAdapter,
+) error {
+	eventProcessor, err := cqrs.NewEventProcessorWithConfig(router, cqrs.EventProcessorConfig{
+		GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+			return Topic(consumerGroup, params.EventName), nil
+		},
+		SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+			return broker.Subscriber(consumerGroupPrefix + "." + params.HandlerName)
+		},
+		Marshaler: marshaler,
+		Logger:    logger,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create replay event processor: %w", err)
+	}
+
+	var replaySafeHandlers []cqrs.EventHandler
+	for _, handler := range handlers {
+		if event.IsReplaySafe(handler.HandlerName()) {
+			replaySafeHandlers = append(replaySafeHandlers, consumerGroupHandler{EventHandler: handler, consumerGroup: consumerGroup})
+		}
+	}
+
+	if err := eventProcessor.AddHandlers(replaySafeHandlers...); err != nil {
+		return fmt.Errorf("could not add handlers to replay event processor: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"tickets/entities"
+	ticketsMessage "tickets/message"
+	"tickets/message/event"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"golang.org/x/sync/errgroup"
+)
+
+const batchSize = 100
+
+type Config struct {
+	// HandlerName replays events to a single event handler, running in this process.
+	HandlerName string
+	// ConsumerGroup replays events to "replay.<ConsumerGroup>.<EventName>" topics, consumed by services
+	// started with the same replay consumer group, see RegisterConsumerGroupHandlers.
+	// Only replay-safe handlers consume them, and production handlers aren't touched.
+	ConsumerGroup string
+
+	EventNames []string
+	From       *time.Time
+	To         *time.Time
+
+	// RatePerSecond limits how many events are replayed per second, 0 means no limit.
+	RatePerSecond float64
+
+	// AllowSideEffects allows replaying to a handler calling external services or publishing events.
+	// It's used only with HandlerName.
+	AllowSideEffects bool
+}
+
+func (c Config) Validate() error {
+	if (c.HandlerName == "") == (c.ConsumerGroup == "") {
+		return errors.New("exactly one of handler name and consumer group must be set")
+	}
+	if c.RatePerSecond < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if c.From != nil && c.To != nil && c.From.After(*c.To) {
+		return errors.New("from must be before to")
+	}
+
+	return nil
+}
+
+type EventRepository interface {
+	All(ctx context.Context, filter entities.StoredEventsFilter) ([]entities.StoredEvent, error)
+}
+
+type Replayer struct {
+	repository EventRepository
+	handlers   []cqrs.EventHandler
+	publisher  message.Publisher
+	policies   *ticketsMessage.HandlerPolicies
+	logger     watermill.LoggerAdapter
+}
+
+func NewReplayer(
+	repository EventRepository,
+	handlers []cqrs.EventHandler,
+	publisher message.Publisher,
+	policies *ticketsMessage.HandlerPolicies,
+	logger watermill.LoggerAdapter,
+) *Replayer {
+	return &Replayer{
+		repository: repository,
+		handlers:   handlers,
+		publisher:  publisher,
+		policies:   policies,
+		logger:     logger,
+	}
+}
+
+func (r *Replayer) Run(ctx context.Context, config Config) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid replay config: %w", err)
+	}
+
+	if config.ConsumerGroup != "" {
+		return r.replayToConsumerGroup(ctx, config)
+	}
+
+	return r.replayToHandler(ctx, config)
+}
+
+// replayToConsumerGroup publishes the events to the topics of the consumer group.
+// Events failing there are handled like live events, with retries and the poison queue of the consuming service.
+func (r *Replayer) replayToConsumerGroup(ctx context.Context, config Config) error {
+	replayed, err := r.replay(ctx, config, r.publisher, func(eventName string) string {
+		return Topic(config.ConsumerGroup, eventName)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Infof("Replayed %d events to consumer group %s", replayed, config.ConsumerGroup)
+
+	return nil
+}
+
+// replayToHandler replays the events to the handler, and fails if any of them failed all retries of the handler.
+func (r *Replayer) replayToHandler(ctx context.Context, config Config) error {
+	var handler cqrs.EventHandler
+	for _, h := range r.handlers {
+		if h.HandlerName() == config.HandlerName {
+			handler = h
+			break
+		}
+	}
+	if handler == nil {
+		return fmt.Errorf("unknown handler: %s", config.HandlerName)
+	}
+
+	if !event.IsReplaySafe(handler.HandlerName()) && !config.AllowSideEffects {
+		return fmt.Errorf("handler %s has side effects, replaying it has to be explicitly allowed", handler.HandlerName())
+	}
+
+	// the handler only gets the events it's subscribed to
+	eventName := event.JSONMarshaler.Name(handler.NewEvent())
+	if len(config.EventNames) > 0 && !contains(config.EventNames, eventName) {
+		return fmt.Errorf("handler %s doesn't handle any of %v", handler.HandlerName(), config.EventNames)
+	}
+	config.EventNames = []string{eventName}
+
+	// publishing blocks until the handler acks the message, so events are handled one by one
+	pubSub := gochannel.NewGoChannel(gochannel.Config{BlockPublishUntilSubscriberAck: true}, r.logger)
+
+	// events failing all retries are counted instead of poisoned, so the replay is reported as incomplete
+	failed := &failedEvents{}
+	router := ticketsMessage.NewWatermillRouter(ticketsMessage.RouterConfig{
+		PoisonQueuePublisher: failed,
+		HandlerPolicies:      r.policies,
+	}, r.logger)
+
+	eventProcessor, err := cqrs.NewEventProcessorWithConfig(router, cqrs.EventProcessorConfig{
+		GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+			return params.EventName, nil
+		},
+		SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+			return pubSub, nil
+		},
+		Marshaler: event.JSONMarshaler,
+		Logger:    r.logger,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create replay event processor: %w", err)
+	}
+
+	if err := eventProcessor.AddHandlers(handler); err != nil {
+		return fmt.Errorf("could not add handler to replay event processor: %w", err)
+	}
+
+	errgrp, errgrpCtx := errgroup.WithContext(ctx)
+	routerCtx, cancelRouter := context.WithCancel(errgrpCtx)
+	defer cancelRouter()
+
+	errgrp.Go(func() error {
+		return router.Run(routerCtx)
+	})
+
+	replayed := 0
+	errgrp.Go(func() error {
+		defer cancelRouter()
+
+		select {
+		case <-router.Running():
+		case <-errgrpCtx.Done():
+			return errgrpCtx.Err()
+		}
+
+		var err error
+		replayed, err = r.replay(errgrpCtx, config, pubSub, func(eventName string) string {
+			return eventName
+		})
+		return err
+	})
+
+	if err := errgrp.Wait(); err != nil {
+		return err
+	}
+
+	if failedCount := failed.Count(); failedCount > 0 {
+		return fmt.Errorf("%d of %d events failed to replay to %s", failedCount, replayed, handler.HandlerName())
+	}
+
+	log.FromContext(ctx).Infof("Replayed %d events to %s", replayed, handler.HandlerName())
+
+	return nil
+}
+
+// replay publishes the stored events in batches, and returns how many were published.
+func (r *Replayer) replay(
+	ctx context.Context,
+	config Config,
+	publisher message.Publisher,
+	topic func(eventName string) string,
+) (int, error) {
+	var ticker *time.Ticker
+	if config.RatePerSecond > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / config.RatePerSecond))
+		defer ticker.Stop()
+	}
+
+	filter := entities.StoredEventsFilter{
+		EventNames: config.EventNames,
+		From:       config.From,
+		To:         config.To,
+		Limit:      batchSize,
+	}
+
+	replayed := 0
+	for {
+		events, err := r.repository.All(ctx, filter)
+		if err != nil {
+			return replayed, fmt.Errorf("could not fetch events to replay: %w", err)
+		}
+
+		for _, storedEvent := range events {
+			if ticker != nil {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return replayed, ctx.Err()
+				}
+			}
+
+			msg := message.NewMessage(watermill.NewUUID(), message.Payload(storedEvent.Payload))
+			msg.Metadata.Set("name", storedEvent.EventName)
+			msg.Metadata.Set("correlation_id", storedEvent.CorrelationID)
+			msg.Metadata.Set(event.VersionMetadataKey, strconv.Itoa(storedEvent.Version))
+
+			if err := publisher.Publish(topic(storedEvent.EventName), msg); err != nil {
+				return replayed, fmt.Errorf("could not replay event %s: %w", storedEvent.EventID, err)
+			}
+
+			replayed++
+		}
+
+		if len(events) < batchSize {
+			return replayed, nil
+		}
+		cursor := events[len(events)-1].Cursor()
+		filter.After = &cursor
+	}
+}
+
+// failedEvents is the poison queue of the replay, it counts events which failed all retries of the handler.
+type failedEvents struct {
+	lock  sync.Mutex
+	count int
+}
+
+func (f *failedEvents) Publish(topic string, messages ...*message.Message) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, msg := range messages {
+		log.FromContext(msg.Context()).Errorf(
+			"Event %s failed to replay: %s",
+			msg.Metadata.Get("name"),
+			msg.Metadata.Get(middleware.ReasonForPoisonedKey),
+		)
+	}
+	f.count += len(messages)
+
+	return nil
+}
+
+func (f *failedEvents) Close() error {
+	return nil
+}
+
+func (f *failedEvents) Count() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.count
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
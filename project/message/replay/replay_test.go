@@ -0,0 +1,233 @@
+package replay_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tickets/db/memory"
+	"tickets/entities"
+	ticketsMessage "tickets/message"
+	"tickets/message/event"
+	"tickets/message/replay"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	testCases := []struct {
+		Name        string
+		Config      replay.Config
+		ExpectedErr bool
+	}{
+		{
+			Name:   "handler",
+			Config: replay.Config{HandlerName: "OpsBookings.BookingMade"},
+		},
+		{
+			Name:   "consumer_group",
+			Config: replay.Config{ConsumerGroup: "rebuild", From: &earlier, To: &now, RatePerSecond: 10},
+		},
+		{
+			Name:   "handler_with_filters",
+			Config: replay.Config{HandlerName: "StoreReceipt", From: &earlier, To: &now, RatePerSecond: 10},
+		},
+		{
+			Name:        "nothing_to_replay_to",
+			Config:      replay.Config{},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "handler_and_consumer_group",
+			Config:      replay.Config{HandlerName: "StoreReceipt", ConsumerGroup: "rebuild"},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "negative_rate",
+			Config:      replay.Config{HandlerName: "StoreReceipt", RatePerSecond: -1},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "from_after_to",
+			Config:      replay.Config{HandlerName: "StoreReceipt", From: &now, To: &earlier},
+			ExpectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Config.Validate()
+			if tc.ExpectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReplayer_Run(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewEventRepository()
+
+	// more events than in one batch, pairs of them published at the same time
+	publishedAt := time.Now().UTC().Add(-time.Hour)
+	var brokenTicketID string
+	for i := 0; i < 250; i++ {
+		ticketRefunded := entities.TicketRefunded{
+			Header:   entities.NewEventHeader(),
+			TicketID: uuid.NewString(),
+		}
+		if i == 120 {
+			brokenTicketID = ticketRefunded.TicketID
+		}
+
+		payload, err := json.Marshal(ticketRefunded)
+		require.NoError(t, err)
+
+		require.NoError(t, repository.Append(ctx, entities.StoredEvent{
+			EventID:     uuid.NewString(),
+			EventName:   "TicketRefunded",
+			Version:     event.JSONMarshaler.Schemas.CurrentVersion("TicketRefunded"),
+			PublishedAt: publishedAt.Add(time.Duration(i/2) * time.Millisecond),
+			Payload:     payload,
+		}))
+	}
+
+	var lock sync.Mutex
+	handled := map[string]int{}
+	handler := cqrs.NewEventHandler(
+		"OpsBookings.TicketRefunded",
+		func(ctx context.Context, event *entities.TicketRefunded) error {
+			lock.Lock()
+			defer lock.Unlock()
+
+			handled[event.TicketID]++
+			if event.TicketID == brokenTicketID {
+				return errors.New("broken event")
+			}
+			return nil
+		},
+	)
+
+	replayer := replay.NewReplayer(
+		repository,
+		[]cqrs.EventHandler{handler},
+		nil,
+		ticketsMessage.NewHandlerPolicies(ticketsMessage.HandlerPolicy{}),
+		watermill.NopLogger{},
+	)
+
+	err := replayer.Run(ctx, replay.Config{HandlerName: "OpsBookings.TicketRefunded"})
+	assert.ErrorContains(t, err, "1 of 250 events failed")
+
+	assert.Len(t, handled, 250)
+	for ticketID, count := range handled {
+		assert.Equal(t, 1, count, "event of ticket %s should be replayed once", ticketID)
+	}
+}
+
+func TestReplayer_Run_consumerGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repository := memory.NewEventRepository()
+	var ticketIDs []string
+	for i := 0; i < 3; i++ {
+		ticketRefunded := entities.TicketRefunded{
+			Header:   entities.NewEventHeader(),
+			TicketID: uuid.NewString(),
+		}
+		ticketIDs = append(ticketIDs, ticketRefunded.TicketID)
+
+		payload, err := json.Marshal(ticketRefunded)
+		require.NoError(t, err)
+
+		require.NoError(t, repository.Append(ctx, entities.StoredEvent{
+			EventID:     uuid.NewString(),
+			EventName:   "TicketRefunded",
+			Version:     event.JSONMarshaler.Schemas.CurrentVersion("TicketRefunded"),
+			PublishedAt: ticketRefunded.Header.PublishedAt,
+			Payload:     payload,
+		}))
+	}
+
+	var lock sync.Mutex
+	var projected []string
+	var sideEffects int
+	handlers := []cqrs.EventHandler{
+		cqrs.NewEventHandler(
+			"OpsBookings.TicketRefunded",
+			func(ctx context.Context, event *entities.TicketRefunded) error {
+				lock.Lock()
+				defer lock.Unlock()
+
+				projected = append(projected, event.TicketID)
+				return nil
+			},
+		),
+		cqrs.NewEventHandler(
+			"RefundTicketInSpreadsheet",
+			func(ctx context.Context, event *entities.TicketRefunded) error {
+				lock.Lock()
+				defer lock.Unlock()
+
+				sideEffects++
+				return nil
+			},
+		),
+	}
+
+	broker := ticketsMessage.NewGoChannelBroker("", watermill.NopLogger{})
+	router := ticketsMessage.NewWatermillRouter(ticketsMessage.RouterConfig{
+		PoisonQueuePublisher: broker.Publisher(),
+		HandlerPolicies:      ticketsMessage.NewHandlerPolicies(ticketsMessage.HandlerPolicy{}),
+	}, watermill.NopLogger{})
+
+	err := replay.RegisterConsumerGroupHandlers(
+		router,
+		broker,
+		"svc-tickets",
+		"rebuild",
+		handlers,
+		event.NewMarshaler(event.FormatJSON),
+		watermill.NopLogger{},
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = router.Run(ctx)
+	}()
+	<-router.Running()
+
+	replayer := replay.NewReplayer(
+		repository,
+		handlers,
+		broker.Publisher(),
+		ticketsMessage.NewHandlerPolicies(ticketsMessage.HandlerPolicy{}),
+		watermill.NopLogger{},
+	)
+	require.NoError(t, replayer.Run(ctx, replay.Config{ConsumerGroup: "rebuild"}))
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return len(projected) == len(ticketIDs)
+	}, time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.ElementsMatch(t, ticketIDs, projected)
+	assert.Zero(t, sideEffects, "handlers with side effects should not consume replayed events")
+}
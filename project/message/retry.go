@@ -0,0 +1,134 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+)
+
+const (
+	RetryAttemptMetadataKey = "retry_attempt"
+	RetryHandlerMetadataKey = "retry_handler"
+)
+
+// DefaultRetryDelays are the delays of the following delayed retries, the message is poisoned after the last one.
+var DefaultRetryDelays = []time.Duration{10 * time.Second, time.Minute, 10 * time.Minute}
+
+type MessageScheduler interface {
+	Schedule(ctx context.Context, msg entities.ScheduledMessage) error
+}
+
+// DelayedRetry handles messages which failed all in-process retries of the handler's policy.
+// Instead of blocking the consumer, the message is acked and scheduled to be published again later,
+// with the attempt number in its metadata. When all delays are used, the error goes to the poison queue.
+type DelayedRetry struct {
+	scheduler MessageScheduler
+	delays    []time.Duration
+}
+
+func NewDelayedRetry(scheduler MessageScheduler, delays []time.Duration) *DelayedRetry {
+	if scheduler == nil {
+		panic("scheduler is required")
+	}
+
+	return &DelayedRetry{
+		scheduler: scheduler,
+		delays:    delays,
+	}
+}
+
+// RetryAttempt returns how many delayed retries of the message were already scheduled.
+func RetryAttempt(msg *message.Message) int {
+	attempt, err := strconv.Atoi(msg.Metadata.Get(RetryAttemptMetadataKey))
+	if err != nil {
+		return 0
+	}
+
+	return attempt
+}
+
+func (r *DelayedRetry) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		events, err := h(msg)
+		if err == nil || errors.Is(err, ErrShuttingDown) {
+			return events, err
+		}
+
+		attempt := RetryAttempt(msg)
+		if attempt >= len(r.delays) {
+			return nil, err
+		}
+
+		ctx := msg.Context()
+		handlerName := message.HandlerNameFromCtx(ctx)
+		delay := r.delays[attempt]
+
+		metadata := make(map[string]string, len(msg.Metadata)+2)
+		for key, value := range msg.Metadata {
+			metadata[key] = value
+		}
+		metadata[RetryAttemptMetadataKey] = strconv.Itoa(attempt + 1)
+		metadata[RetryHandlerMetadataKey] = handlerName
+
+		scheduleErr := r.scheduler.Schedule(ctx, entities.ScheduledMessage{
+			ID:          uuid.New(),
+			MessageUUID: msg.UUID,
+			Topic:       message.SubscribeTopicFromCtx(ctx),
+			Payload:     msg.Payload,
+			Metadata:    metadata,
+			DeliverAt:   time.Now().Add(delay),
+		})
+		if scheduleErr != nil {
+			return nil, fmt.Errorf("could not schedule retry of failed message (%w): %w", err, scheduleErr)
+		}
+
+		log.FromContext(ctx).WithError(err).Warnf(
+			"Handler %s failed, message scheduled for retry %d of %d in %s",
+			handlerName,
+			attempt+1,
+			len(r.delays),
+			delay,
+		)
+
+		return nil, nil
+	}
+}
+
+// retriedMessageFilter skips retried messages in handlers other than the one which failed.
+// Retries are published to the original topic, so every consumer group of the topic gets them.
+func retriedMessageFilter(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		retryHandler := msg.Metadata.Get(RetryHandlerMetadataKey)
+		if retryHandler != "" && retryHandler != message.HandlerNameFromCtx(msg.Context()) {
+			return nil, nil
+		}
+
+		return h(msg)
+	}
+}
+
+// exceptHandlers applies the middleware to all handlers except the listed ones.
+func exceptHandlers(middleware message.HandlerMiddleware, handlerNames ...string) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		wrapped := middleware(h)
+
+		return func(msg *message.Message) ([]*message.Message, error) {
+			handlerName := message.HandlerNameFromCtx(msg.Context())
+			for _, name := range handlerNames {
+				if name == handlerName {
+					return h(msg)
+				}
+			}
+
+			return wrapped(msg)
+		}
+	}
+}
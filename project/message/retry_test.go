@@ -0,0 +1,93 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type messageSchedulerMock struct {
+	scheduled []entities.ScheduledMessage
+}
+
+func (m *messageSchedulerMock) Schedule(ctx context.Context, msg entities.ScheduledMessage) error {
+	m.scheduled = append(m.scheduled, msg)
+	return nil
+}
+
+func TestDelayedRetry(t *testing.T) {
+	scheduler := &messageSchedulerMock{}
+	delays := []time.Duration{10 * time.Second, time.Minute}
+	handlerErr := errors.New("receipts API is down")
+
+	handler := NewDelayedRetry(scheduler, delays).Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		return nil, handlerErr
+	})
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte(`{}`))
+	msg.Metadata.Set("correlation_id", "correlation-id")
+
+	_, err := handler(msg)
+	require.NoError(t, err, "message should be acked and retried later")
+
+	require.Len(t, scheduler.scheduled, 1)
+	scheduled := scheduler.scheduled[0]
+	assert.Equal(t, msg.UUID, scheduled.MessageUUID)
+	assert.Equal(t, "1", scheduled.Metadata[RetryAttemptMetadataKey])
+	assert.Equal(t, "correlation-id", scheduled.Metadata["correlation_id"])
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), scheduled.DeliverAt, time.Second)
+
+	msg.Metadata.Set(RetryAttemptMetadataKey, "1")
+	_, err = handler(msg)
+	require.NoError(t, err)
+	require.Len(t, scheduler.scheduled, 2)
+	assert.Equal(t, "2", scheduler.scheduled[1].Metadata[RetryAttemptMetadataKey])
+	assert.WithinDuration(t, time.Now().Add(time.Minute), scheduler.scheduled[1].DeliverAt, time.Second)
+
+	// all delayed retries are used, so the error goes to the poison queue
+	msg.Metadata.Set(RetryAttemptMetadataKey, "2")
+	_, err = handler(msg)
+	assert.ErrorIs(t, err, handlerErr)
+	assert.Len(t, scheduler.scheduled, 2)
+}
+
+func TestRetriedMessageFilter(t *testing.T) {
+	called := false
+	handler := retriedMessageFilter(func(msg *message.Message) ([]*message.Message, error) {
+		called = true
+		return nil, nil
+	})
+
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	msg.Metadata.Set(RetryHandlerMetadataKey, "IssueReceipt")
+
+	_, err := handler(msg)
+	require.NoError(t, err)
+	assert.False(t, called, "retry of another handler should be skipped")
+
+	called = false
+	msg.Metadata.Set(RetryHandlerMetadataKey, "")
+	_, err = handler(msg)
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestDelayedRetry_skipsShutdown(t *testing.T) {
+	scheduler := &messageSchedulerMock{}
+
+	handler := NewDelayedRetry(scheduler, []time.Duration{time.Second}).Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		return nil, errors.Join(ErrShuttingDown, context.Canceled)
+	})
+
+	_, err := handler(message.NewMessage(watermill.NewUUID(), nil))
+	assert.ErrorIs(t, err, ErrShuttingDown)
+	assert.Empty(t, scheduler.scheduled)
+}
@@ -1,21 +1,35 @@
 package message
 
 import (
+	"time"
+
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
-const brokenMessageID = "2beaf5bc-d5e4-4653-b075-2b36bbf28949"
+type RouterConfig struct {
+	// PoisonQueuePublisher gets messages failing all retries, so a single broken message doesn't block its consumer group.
+	PoisonQueuePublisher message.Publisher
+	// HandlerPolicies decide about in-process retries and timeouts of every handler.
+	HandlerPolicies *HandlerPolicies
+	// DelayedRetry is optional, without it messages failing in-process retries go to the poison queue right away.
+	DelayedRetry *DelayedRetry
+	// Drain is optional, it lets in-flight messages finish when the service shuts down.
+	Drain *Drain
+	// CloseTimeout limits how long closing the router waits for handlers, 0 means Watermill's default.
+	CloseTimeout time.Duration
+}
 
 func NewWatermillRouter(
+	config RouterConfig,
 	watermillLogger watermill.LoggerAdapter,
 ) *message.Router {
-	router, err := message.NewRouter(message.RouterConfig{}, watermillLogger)
+	router, err := message.NewRouter(message.RouterConfig{CloseTimeout: config.CloseTimeout}, watermillLogger)
 	if err != nil {
 		panic(err)
 	}
 
-	useMiddlewares(router, watermillLogger)
+	useMiddlewares(router, config)
 
 	return router
 }
@@ -0,0 +1,72 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"tickets/entities"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+const scheduledMessagesBatchSize = 100
+
+type ScheduledMessageRepository interface {
+	DeliverDue(ctx context.Context, limit int, deliver func(msg entities.ScheduledMessage) error) (int, error)
+}
+
+// Scheduler publishes scheduled messages when they are due.
+type Scheduler struct {
+	repository   ScheduledMessageRepository
+	publisher    message.Publisher
+	pollInterval time.Duration
+}
+
+func NewScheduler(repository ScheduledMessageRepository, publisher message.Publisher, pollInterval time.Duration) *Scheduler {
+	if repository == nil {
+		panic("scheduled message repository is required")
+	}
+	if publisher == nil {
+		panic("publisher is required")
+	}
+
+	return &Scheduler{
+		repository:   repository,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for {
+			delivered, err := s.repository.DeliverDue(ctx, scheduledMessagesBatchSize, s.deliver)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Error("Could not deliver scheduled messages")
+				break
+			}
+			if delivered < scheduledMessagesBatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (s *Scheduler) deliver(scheduled entities.ScheduledMessage) error {
+	msg := message.NewMessage(scheduled.MessageUUID, scheduled.Payload)
+	for key, value := range scheduled.Metadata {
+		msg.Metadata.Set(key, value)
+	}
+
+	return s.publisher.Publish(scheduled.Topic, msg)
+}
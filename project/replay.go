@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"tickets/message/replay"
+)
+
+// parseReplayConfig parses arguments of the "replay" subcommand, for example:
+//
+//	tickets replay -handler OpsBookings.BookingMade -from 2024-01-01T00:00:00Z -rate 50
+//
+// or, to rebuild the read models of a service started with -replay-consumer-group rebuild:
+//
+//	tickets replay -consumer-group rebuild -rate 50
+func parseReplayConfig(args []string) (replay.Config, error) {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+
+	handlerName := flags.String("handler", "", "name of the event handler to replay events to")
+	consumerGroup := flags.String("consumer-group", "", "temporary consumer group to replay events to")
+	eventNames := flags.String("events", "", "comma separated names of events to replay")
+	from := flags.String("from", "", "replay events published at or after this time (RFC3339)")
+	to := flags.String("to", "", "replay events published at or before this time (RFC3339)")
+	rate := flags.Float64("rate", 0, "maximum number of events replayed per second, 0 means no limit")
+	allowSideEffects := flags.Bool("allow-side-effects", false, "allow replaying to handlers with side effects")
+
+	if err := flags.Parse(args); err != nil {
+		return replay.Config{}, err
+	}
+
+	config := replay.Config{
+		HandlerName:      *handlerName,
+		ConsumerGroup:    *consumerGroup,
+		RatePerSecond:    *rate,
+		AllowSideEffects: *allowSideEffects,
+	}
+
+	if *eventNames != "" {
+		for _, name := range strings.Split(*eventNames, ",") {
+			config.EventNames = append(config.EventNames, strings.TrimSpace(name))
+		}
+	}
+
+	var err error
+	if config.From, err = parseReplayTime(*from); err != nil {
+		return replay.Config{}, fmt.Errorf("invalid -from: %w", err)
+	}
+	if config.To, err = parseReplayTime(*to); err != nil {
+		return replay.Config{}, fmt.Errorf("invalid -to: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return replay.Config{}, err
+	}
+
+	return config, nil
+}
+
+func parseReplayTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"tickets/config"
+	"tickets/message"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+)
+
+// NewBroker creates the broker of the configured backend, it has to be closed after the service stops.
+func NewBroker(cfg config.Config, stdDB *sql.DB) (*message.Broker, error) {
+	watermillLogger := log.NewWatermill(log.FromContext(context.Background()))
+	namespace := cfg.Messaging.Namespace
+
+	switch cfg.Broker.Backend {
+	case message.BrokerRedis:
+		return message.NewRedisBroker(message.NewRedisClient(cfg.Redis.Addr), namespace, watermillLogger)
+	case message.BrokerPostgres:
+		return message.NewPostgresBroker(stdDB, namespace, cfg.Broker.PollInterval, watermillLogger)
+	case message.BrokerGoChannel:
+		return message.NewGoChannelBroker(namespace, watermillLogger), nil
+	default:
+		return nil, fmt.Errorf("unknown broker %q", cfg.Broker.Backend)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"tickets/config"
+	ticketsHttp "tickets/http"
+	"tickets/message"
+	"tickets/message/outbox"
+
+	watermillMessage "github.com/ThreeDotsLabs/watermill/message"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newHealth checks only the dependencies used with the config, for example Postgres isn't checked with the memory storage.
+// The outbox forwarder is nil when the outbox isn't used.
+func newHealth(
+	cfg config.Config,
+	postgres *pgxpool.Pool,
+	stdDB *sql.DB,
+	broker *message.Broker,
+	router *watermillMessage.Router,
+	forwarder *outbox.Forwarder,
+) *ticketsHttp.Health {
+	checks := []ticketsHttp.HealthCheck{
+		{Name: "broker", Check: broker.Ping},
+		{Name: "router", Check: func(ctx context.Context) error {
+			if !router.IsRunning() || router.IsClosed() {
+				return errors.New("router is not running")
+			}
+			return nil
+		}},
+	}
+
+	if postgres != nil {
+		checks = append(checks, ticketsHttp.HealthCheck{Name: "postgres", Check: postgres.Ping})
+	}
+	if stdDB != nil {
+		checks = append(checks, ticketsHttp.HealthCheck{Name: "postgres_sql", Check: stdDB.PingContext})
+	}
+
+	if forwarder != nil {
+		checks = append(checks, ticketsHttp.HealthCheck{Name: "outbox_forwarder", Check: func(ctx context.Context) error {
+			if !forwarder.Running() {
+				return errors.New("outbox forwarder is not running")
+			}
+			return nil
+		}})
+	}
+
+	if cfg.Health.ProbeGateway {
+		checks = append(checks, ticketsHttp.HealthCheck{
+			Name:     "gateway",
+			Check:    func(ctx context.Context) error { return probeGateway(ctx, cfg.GatewayAddr) },
+			Optional: true,
+		})
+	}
+
+	return ticketsHttp.NewHealth(cfg.Health.CheckTimeout, checks...)
+}
+
+// probeGateway checks that the gateway responds, the response itself doesn't matter unless it's a server error.
+func probeGateway(ctx context.Context, gatewayAddr string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gatewayAddr, nil)
+	if err != nil {
+		return fmt.Errorf("invalid gateway address: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("gateway responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
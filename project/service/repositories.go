@@ -0,0 +1,108 @@
+package service
+
+import (
+	"database/sql"
+
+	"tickets/db"
+	"tickets/db/memory"
+	ticketsHttp "tickets/http"
+	"tickets/message"
+	"tickets/message/event"
+	"tickets/message/replay"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The repository interfaces combine the interfaces of all packages using the repository.
+
+type ticketRepository interface {
+	event.TicketsRepository
+	ticketsHttp.TicketRepository
+}
+
+type showRepository interface {
+	event.ShowsRepository
+	ticketsHttp.ShowRepository
+}
+
+type receiptRepository interface {
+	event.ReceiptsRepository
+	ticketsHttp.ReceiptRepository
+}
+
+type opsBookingsRepository interface {
+	event.OpsBookingsRepository
+	ticketsHttp.OpsBookingsRepository
+}
+
+type eventRepository interface {
+	event.EventStoreRepository
+	ticketsHttp.EventRepository
+	replay.EventRepository
+}
+
+type poisonedMessageRepository interface {
+	message.PoisonedMessageRepository
+	ticketsHttp.PoisonedMessageRepository
+}
+
+type scheduledMessageRepository interface {
+	message.MessageScheduler
+	message.ScheduledMessageRepository
+}
+
+type repositories struct {
+	tickets           ticketRepository
+	shows             showRepository
+	bookings          ticketsHttp.BookingRepository
+	receipts          receiptRepository
+	opsBookings       opsBookingsRepository
+	events            eventRepository
+	idempotencyKeys   ticketsHttp.IdempotencyKeyRepository
+	processedEvents   event.ProcessedEventsRepository
+	poisonedMessages  poisonedMessageRepository
+	scheduledMessages scheduledMessageRepository
+}
+
+// newPostgresRepositories creates repositories storing data in Postgres.
+// Bookings publish their events to the outbox, in the same transaction.
+func newPostgresRepositories(
+	postgres *pgxpool.Pool,
+	stdDB *sql.DB,
+	eventMarshaler cqrs.CommandEventMarshaler,
+	namespace message.Namespace,
+	outboxTopic string,
+) repositories {
+	return repositories{
+		tickets:           db.NewTicketRepository(postgres),
+		shows:             db.NewShowRepository(postgres),
+		bookings:          db.NewBookingRepository(postgres, stdDB, eventMarshaler, outboxTopic),
+		receipts:          db.NewReceiptRepository(postgres),
+		opsBookings:       db.NewOpsBookingsRepository(postgres),
+		events:            db.NewEventRepository(postgres),
+		idempotencyKeys:   db.NewIdempotencyKeyRepository(postgres),
+		processedEvents:   db.NewProcessedEventsRepository(postgres),
+		poisonedMessages:  db.NewPoisonedMessageRepository(postgres),
+		scheduledMessages: db.NewScheduledMessageRepository(postgres, string(namespace)),
+	}
+}
+
+// newMemoryRepositories creates repositories keeping data in memory.
+// Bookings publish their events directly to the event bus.
+func newMemoryRepositories(eventBus *cqrs.EventBus) repositories {
+	shows := memory.NewShowRepository()
+
+	return repositories{
+		tickets:           memory.NewTicketRepository(),
+		shows:             shows,
+		bookings:          memory.NewBookingRepository(shows, eventBus),
+		receipts:          memory.NewReceiptRepository(),
+		opsBookings:       memory.NewOpsBookingsRepository(),
+		events:            memory.NewEventRepository(),
+		idempotencyKeys:   memory.NewIdempotencyKeyRepository(),
+		processedEvents:   memory.NewProcessedEventsRepository(),
+		poisonedMessages:  memory.NewPoisonedMessageRepository(),
+		scheduledMessages: memory.NewScheduledMessageRepository(),
+	}
+}
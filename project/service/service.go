@@ -5,18 +5,21 @@ import (
 	"database/sql"
 	"fmt"
 	stdHTTP "net/http"
-	"tickets/message/outbox"
+	"time"
 
+	"tickets/config"
 	"tickets/db"
 	ticketsHttp "tickets/http"
 	"tickets/message"
+	"tickets/message/command"
 	"tickets/message/event"
+	"tickets/message/outbox"
+	"tickets/message/replay"
 
 	"github.com/ThreeDotsLabs/go-event-driven/common/log"
 	watermillMessage "github.com/ThreeDotsLabs/watermill/message"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
-	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
@@ -25,83 +28,216 @@ func init() {
 	log.Init(logrus.InfoLevel)
 }
 
+type ReceiptsService interface {
+	event.ReceiptsService
+	command.ReceiptsService
+}
+
 type Service struct {
+	// db and stdDB are nil when Postgres isn't used
 	db              *pgxpool.Pool
+	stdDB           *sql.DB
+	broker          *message.Broker
 	watermillRouter *watermillMessage.Router
+	drain           *message.Drain
 	echoRouter      *echo.Echo
+	health          *ticketsHttp.Health
+	replayer        *replay.Replayer
+	scheduler       *message.Scheduler
+	httpAddr        string
+	shutdownTimeout time.Duration
+	shutdownDelay   time.Duration
+	// outboxPollInterval is 0 when the outbox isn't used
+	outboxPollInterval time.Duration
 }
 
 func New(
-	redisClient *redis.Client,
+	cfg config.Config,
+	broker *message.Broker,
 	postgres *pgxpool.Pool,
 	stdDB *sql.DB,
 	spreadsheetsService event.SpreadsheetsAPI,
-	receiptsService event.ReceiptsService,
+	receiptsService ReceiptsService,
 	filesService event.FilesAPI,
+	deadNationAPI event.DeadNationAPI,
+	paymentsService command.PaymentsService,
 ) Service {
 	watermillLogger := log.NewWatermill(log.FromContext(context.Background()))
 
-	var redisPublisher watermillMessage.Publisher
-	redisPublisher = message.NewRedisPublisher(redisClient, watermillLogger)
-	redisPublisher = log.CorrelationPublisherDecorator{Publisher: redisPublisher}
+	consumerGroupPrefix := cfg.Messaging.ConsumerGroupPrefix
 
-	eventBus := event.NewEventBus(redisPublisher)
+	publisher := broker.Publisher()
 
-	watermillRouter := message.NewWatermillRouter(
-		watermillLogger,
-	)
+	eventMarshaler := event.NewMarshaler(cfg.Messaging.EventsFormat)
+
+	eventBus := event.NewEventBus(publisher, eventMarshaler)
+	commandBus := command.NewCommandBus(publisher)
+
+	handlerPolicies := message.NewHandlerPolicies(cfg.Handler)
 
-	ticketRepository := db.NewTicketRepository(postgres)
-	showRepository := db.NewShowRepository(postgres)
-	bookingRepository := db.NewBookingRepository(postgres, stdDB)
+	outboxTopic := cfg.Messaging.Namespace.Prefix(outbox.Topic)
+	usePostgres := cfg.Storage.Backend == config.StoragePostgres
 
-	postgresSubscriber := outbox.NewPostgresSubscriber(stdDB, watermillLogger)
-	outbox.AddForwarderHandler(postgresSubscriber, redisPublisher, watermillRouter, watermillLogger)
+	var repos repositories
+	if usePostgres {
+		repos = newPostgresRepositories(postgres, stdDB, eventMarshaler, cfg.Messaging.Namespace, outboxTopic)
+	} else {
+		repos = newMemoryRepositories(eventBus)
+	}
+
+	// the forwarder keeps running while draining, so it can flush the outbox
+	drain := message.NewDrain(outbox.ForwarderHandlerName)
+
+	watermillRouter := message.NewWatermillRouter(message.RouterConfig{
+		PoisonQueuePublisher: publisher,
+		HandlerPolicies:      handlerPolicies,
+		DelayedRetry:         message.NewDelayedRetry(repos.scheduledMessages, cfg.Messaging.RetryDelays),
+		Drain:                drain,
+		CloseTimeout:         cfg.ShutdownTimeout,
+	}, watermillLogger)
+
+	// in memory, events are published without the outbox
+	var outboxPollInterval time.Duration
+	var forwarder *outbox.Forwarder
+	if usePostgres {
+		outboxPollInterval = cfg.Messaging.OutboxPollInterval
+		postgresSubscriber := outbox.NewPostgresSubscriber(stdDB, cfg.Messaging.OutboxPollInterval, watermillLogger)
+		forwarder = outbox.AddForwarderHandler(postgresSubscriber, publisher, watermillRouter, outboxTopic, watermillLogger)
+	}
+
+	eventHandlers := event.NewEventHandlers(
+		spreadsheetsService,
+		receiptsService,
+		deadNationAPI,
+		repos.tickets,
+		repos.shows,
+		repos.receipts,
+		repos.opsBookings,
+		filesService,
+		eventBus,
+	)
 
-	eventProcessorConfig := event.NewProcessorConfig(redisClient, watermillLogger)
+	eventProcessorConfig := event.NewProcessorConfig(broker, consumerGroupPrefix, watermillLogger, eventMarshaler)
 	event.RegisterEventHandlers(
 		watermillRouter,
 		eventProcessorConfig,
-		spreadsheetsService,
+		eventHandlers,
+		repos.processedEvents,
+		handlerPolicies,
+	)
+
+	if cfg.Messaging.ReplayConsumerGroup != "" {
+		err := replay.RegisterConsumerGroupHandlers(
+			watermillRouter,
+			broker,
+			consumerGroupPrefix,
+			cfg.Messaging.ReplayConsumerGroup,
+			eventHandlers,
+			eventMarshaler,
+			watermillLogger,
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	eventStoreSubscriber, err := broker.Subscriber(consumerGroupPrefix + ".EventStore")
+	if err != nil {
+		panic(err)
+	}
+	event.AddEventStoreHandlers(watermillRouter, eventStoreSubscriber, repos.events)
+
+	poisonQueueSubscriber, err := broker.Subscriber(consumerGroupPrefix + ".PoisonQueue")
+	if err != nil {
+		panic(err)
+	}
+	message.AddPoisonQueueHandler(watermillRouter, poisonQueueSubscriber, repos.poisonedMessages)
+
+	commandProcessorConfig := command.NewProcessorConfig(broker, consumerGroupPrefix, watermillLogger)
+	command.RegisterCommandHandlers(
+		watermillRouter,
+		commandProcessorConfig,
+		paymentsService,
 		receiptsService,
-		ticketRepository,
-		filesService,
 		eventBus,
+		handlerPolicies,
 	)
 
+	health := newHealth(cfg, postgres, stdDB, broker, watermillRouter, forwarder)
+
 	echoRouter := ticketsHttp.NewHttpRouter(
 		eventBus,
+		commandBus,
 		spreadsheetsService,
-		ticketRepository,
-		showRepository,
-		bookingRepository,
+		repos.tickets,
+		repos.shows,
+		repos.bookings,
+		repos.receipts,
+		repos.opsBookings,
+		repos.events,
+		repos.idempotencyKeys,
+		repos.poisonedMessages,
+		message.NewPoisonQueue(repos.poisonedMessages, publisher),
+		filesService,
+		health,
+	)
+
+	replayer := replay.NewReplayer(
+		repos.events,
+		eventHandlers,
+		publisher,
+		handlerPolicies,
+		watermillLogger,
 	)
 
 	return Service{
-		postgres,
-		watermillRouter,
-		echoRouter,
+		db:                 postgres,
+		stdDB:              stdDB,
+		broker:             broker,
+		watermillRouter:    watermillRouter,
+		drain:              drain,
+		echoRouter:         echoRouter,
+		health:             health,
+		replayer:           replayer,
+		scheduler:          message.NewScheduler(repos.scheduledMessages, publisher, cfg.Messaging.SchedulerPollInterval),
+		httpAddr:           cfg.HTTP.Addr,
+		shutdownTimeout:    cfg.ShutdownTimeout,
+		shutdownDelay:      cfg.Health.ShutdownDelay,
+		outboxPollInterval: outboxPollInterval,
 	}
 }
 
+// Run runs the service until ctx is done, then it shuts down in order, see shutdown.
+// The broker and the databases are closed when Run returns.
 func (s Service) Run(
 	ctx context.Context,
 ) error {
-	if err := db.CreateDatabaseSchema(s.db); err != nil {
-		return fmt.Errorf("error creating database schema: %w", err)
+	if err := s.createDatabaseSchema(); err != nil {
+		return err
 	}
 
+	// the router and the scheduler aren't stopped by ctx, they are stopped by shutdown when it's their turn
+	runCtx := context.WithoutCancel(ctx)
+	schedulerCtx, stopScheduler := context.WithCancel(runCtx)
+	defer stopScheduler()
+	schedulerStopped := make(chan struct{})
+
 	errgrp, ctx := errgroup.WithContext(ctx)
 
 	errgrp.Go(func() error {
-		return s.watermillRouter.Run(ctx)
+		return s.watermillRouter.Run(runCtx)
+	})
+
+	errgrp.Go(func() error {
+		defer close(schedulerStopped)
+		return s.scheduler.Run(schedulerCtx)
 	})
 
 	errgrp.Go(func() error {
 		// we don't want to start HTTP server before Watermill router (so service won't be healthy before it's ready)
 		<-s.watermillRouter.Running()
 
-		err := s.echoRouter.Start(":8080")
+		err := s.echoRouter.Start(s.httpAddr)
 
 		if err != nil && err != stdHTTP.ErrServerClosed {
 			return err
@@ -112,8 +248,52 @@ func (s Service) Run(
 
 	errgrp.Go(func() error {
 		<-ctx.Done()
-		return s.echoRouter.Shutdown(context.Background())
+		return s.shutdown(func() {
+			stopScheduler()
+			<-schedulerStopped
+		})
 	})
 
 	return errgrp.Wait()
 }
+
+// HTTPAddr returns the address the HTTP server listens on, or an empty string when it's not listening yet.
+// It's useful when the server was started on a random port, for example with the address "127.0.0.1:0".
+func (s Service) HTTPAddr() string {
+	addr := s.echoRouter.ListenerAddr()
+	if addr == nil {
+		return ""
+	}
+
+	return addr.String()
+}
+
+func (s Service) createDatabaseSchema() error {
+	if s.db == nil {
+		return nil
+	}
+
+	if err := db.CreateDatabaseSchema(s.db); err != nil {
+		return fmt.Errorf("error creating database schema: %w", err)
+	}
+
+	return nil
+}
+
+// Replay re-publishes stored events instead of running the service.
+func (s Service) Replay(
+	ctx context.Context,
+	config replay.Config,
+) error {
+	defer func() {
+		if err := s.closeConnections(); err != nil {
+			log.FromContext(ctx).WithError(err).Error("Could not close connections")
+		}
+	}()
+
+	if err := s.createDatabaseSchema(); err != nil {
+		return err
+	}
+
+	return s.replayer.Run(ctx, config)
+}
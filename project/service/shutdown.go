@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tickets/message/outbox"
+
+	"github.com/ThreeDotsLabs/go-event-driven/common/log"
+	"github.com/sirupsen/logrus"
+)
+
+// shutdown stops the service in order, so nothing is cut in the middle:
+//
+//  1. The service reports it's not ready, and after the shutdown delay, when load balancers stopped sending requests,
+//     the HTTP server stops accepting requests and finishes in-flight ones.
+//  2. Handlers finish in-flight messages, new messages are nacked, so they are redelivered after the restart.
+//  3. The scheduler stops, and the outbox forwarder publishes the remaining events.
+//  4. The router closes the subscribers.
+//  5. The broker and the databases are closed.
+//
+// All steps together can take up to the shutdown timeout, messages still handled after it are aborted and nacked.
+func (s Service) shutdown(stopScheduler func()) error {
+	start := time.Now()
+	logger := log.FromContext(context.Background())
+	logger.Info("Shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	s.health.ShuttingDown()
+
+	if s.shutdownDelay > 0 {
+		logger.Infof("Not ready, waiting %s before stopping the HTTP server", s.shutdownDelay)
+		select {
+		case <-time.After(s.shutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	if err := s.echoRouter.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("could not shut down HTTP server: %w", err))
+	}
+
+	s.drain.Start()
+	if err := s.drain.Wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("could not finish in-flight messages: %w", err))
+	}
+
+	stopScheduler()
+
+	outboxFlushed := false
+	if s.outboxPollInterval > 0 {
+		// the forwarder is idle when a few polls of the outbox didn't find anything
+		err := s.drain.WaitIdle(ctx, outbox.ForwarderHandlerName, 2*s.outboxPollInterval)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not flush the outbox: %w", err))
+		} else {
+			outboxFlushed = true
+		}
+	}
+
+	if err := s.closeRouter(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("could not close router: %w", err))
+	}
+
+	if err := s.closeConnections(); err != nil {
+		errs = append(errs, err)
+	}
+
+	stats := s.drain.Stats()
+	summary := logger.WithFields(logrus.Fields{
+		"duration":          time.Since(start).String(),
+		"finished_messages": stats.Finished,
+		"nacked_messages":   stats.Nacked,
+		"aborted_messages":  stats.Aborted,
+		"outbox_flushed":    outboxFlushed,
+	})
+
+	err := errors.Join(errs...)
+	if err != nil {
+		summary.WithError(err).Error("Shutdown finished with errors")
+	} else {
+		summary.Info("Shutdown finished")
+	}
+
+	return err
+}
+
+// closeRouter closes the router within the time left for the shutdown.
+// Watermill's close timeout is fixed when the router is created, so it can't be shortened by the earlier steps.
+func (s Service) closeRouter(ctx context.Context) error {
+	closed := make(chan error, 1)
+	go func() {
+		closed <- s.watermillRouter.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeConnections closes the broker and the databases, after the router and the HTTP server stopped using them.
+func (s Service) closeConnections() error {
+	var errs []error
+
+	if err := s.broker.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("could not close broker: %w", err))
+	}
+
+	if s.db != nil {
+		s.db.Close()
+	}
+
+	if s.stdDB != nil {
+		if err := s.stdDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("could not close database: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
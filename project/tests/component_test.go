@@ -3,55 +3,79 @@ package tests_test
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
 	"tickets/api"
-	"tickets/db"
+	"tickets/config"
 	"tickets/entities"
 	"tickets/message"
 	"tickets/service"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/lib/pq"
 	"github.com/lithammer/shortuuid/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// TestComponent runs fully in-process by default, with the in-memory broker and storage.
+// With COMPONENT_TEST_MODE=infra it uses Redis and Postgres from the environment, like in docker-compose.yaml.
 func TestComponent(t *testing.T) {
-	redisClient := message.NewRedisClient(os.Getenv("REDIS_ADDR"))
-	defer redisClient.Close()
+	cfg := componentTestConfig(t)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	var postgres *pgxpool.Pool
+	var stdDB *sql.DB
+	if cfg.UsesPostgres() {
+		var err error
+		postgres, err = pgxpool.New(context.Background(), cfg.Postgres.URL.Value())
+		require.NoError(t, err)
 
-	postgres, err := pgxpool.New(context.Background(), os.Getenv("POSTGRES_URL"))
-	if err != nil {
-		panic(err)
+		stdDB, err = sql.Open("postgres", cfg.Postgres.URL.Value())
+		require.NoError(t, err)
 	}
 
-	defer postgres.Close()
+	broker, err := service.NewBroker(cfg, stdDB)
+	require.NoError(t, err)
 
 	spreadsheetsService := &api.SpreadsheetsAPIMock{}
 	receiptsService := &api.ReceiptsServiceMock{}
 	fileAPI := &api.FilesAPIClientMock{}
+	deadNationAPI := &api.DeadNationMock{}
+	paymentsService := &api.PaymentsMock{}
+
+	svc := service.New(
+		cfg,
+		broker,
+		postgres,
+		stdDB,
+		spreadsheetsService,
+		receiptsService,
+		fileAPI,
+		deadNationAPI,
+		paymentsService,
+	)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
 	go func() {
-		svc := service.New(
-			redisClient,
-			postgres,
-			spreadsheetsService,
-			receiptsService,
-			fileAPI,
-		)
+		defer close(stopped)
 		assert.NoError(t, svc.Run(ctx))
 	}()
+	// the service shuts down gracefully, and closes the broker and the databases
+	defer func() {
+		cancel()
+		<-stopped
+	}()
 
-	waitForHttpServer(t)
+	baseURL := waitForHttpServer(t, svc)
 
 	ticket := TicketStatus{
 		TicketID: uuid.NewString(),
@@ -68,13 +92,16 @@ func TestComponent(t *testing.T) {
 
 	// test for idempotency
 	for i := 0; i < 3; i++ {
-		sendTicketsStatus(t, TicketsStatusRequest{Tickets: []TicketStatus{ticket}}, idempotencyKey)
+		sendTicketsStatus(t, baseURL, TicketsStatusRequest{Tickets: []TicketStatus{ticket}}, idempotencyKey)
 	}
 
 	assertReceiptForTicketIssued(t, receiptsService, ticket)
+	assertReceiptServed(t, baseURL, ticket)
 	assertTicketUploaded(t, fileAPI, ticket)
 	assertRowToSheetAdded(t, spreadsheetsService, ticket, "tickets-to-print")
-	assertTicketStoredInRepository(t, postgres, ticket)
+	assertTicketStoredInRepository(t, baseURL, ticket)
+	assertTicketFileServed(t, baseURL, ticket)
+	assertTicketFileNotFound(t, baseURL, uuid.NewString())
 
 	failedTicket := TicketStatus{
 		TicketID: uuid.NewString(),
@@ -87,17 +114,111 @@ func TestComponent(t *testing.T) {
 		BookingID: uuid.NewString(),
 	}
 
-	sendTicketsStatus(t, TicketsStatusRequest{Tickets: []TicketStatus{failedTicket}}, uuid.NewString())
+	sendTicketsStatus(t, baseURL, TicketsStatusRequest{Tickets: []TicketStatus{failedTicket}}, uuid.NewString())
 	assertRowToSheetAdded(t, spreadsheetsService, failedTicket, "tickets-to-refund")
+	assertTicketHasStatus(t, baseURL, failedTicket.TicketID, entities.TicketStatusCanceled)
+
+	deadNationID := uuid.NewString()
+	createShowRequest := CreateShowRequest{
+		DeadNationID:    deadNationID,
+		NumberOfTickets: 2,
+		StartTime:       time.Now().Add(24 * time.Hour),
+		Title:           "Component test show",
+		Venue:           "Component test venue",
+		TicketPrice: Money{
+			Amount:   "25.00",
+			Currency: "EUR",
+		},
+	}
+	createShowIdempotencyKey := uuid.NewString()
+	showID := createShow(t, baseURL, createShowRequest, createShowIdempotencyKey)
+
+	// retried request with the same idempotency key doesn't create another show
+	assert.Equal(t, showID, createShow(t, baseURL, createShowRequest, createShowIdempotencyKey))
+
+	bookingID := bookTickets(t, baseURL, BookTicketsRequest{
+		ShowID:          showID,
+		NumberOfTickets: 2,
+		CustomerEmail:   "email@example.com",
+	}, http.StatusCreated)
+
+	assertPlacesBookedInDeadNation(t, deadNationAPI, bookingID, deadNationID, 2)
+
+	for i := 0; i < 2; i++ {
+		bookedTicket := TicketStatus{
+			TicketID: uuid.NewSHA1(uuid.MustParse(bookingID), []byte(strconv.Itoa(i))).String(),
+			Price: Money{
+				Amount:   "25.00",
+				Currency: "EUR",
+			},
+		}
+
+		assertReceiptForTicketIssued(t, receiptsService, bookedTicket)
+		assertTicketStoredInRepository(t, baseURL, bookedTicket)
+	}
+
+	refundedTicketID := ticket.TicketID
+	refundTicket(t, baseURL, refundedTicketID, http.StatusAccepted)
+	assertPaymentRefunded(t, paymentsService, refundedTicketID)
+	assertReceiptVoided(t, receiptsService, refundedTicketID)
+	assertTicketHasStatus(t, baseURL, refundedTicketID, entities.TicketStatusRefunded)
+
+	// a ticket can be refunded only once
+	refundTicket(t, baseURL, refundedTicketID, http.StatusConflict)
+	refundTicket(t, baseURL, uuid.NewString(), http.StatusNotFound)
+
+	// the show is sold out now
+	bookTickets(t, baseURL, BookTicketsRequest{
+		ShowID:          showID,
+		NumberOfTickets: 1,
+		CustomerEmail:   "email@example.com",
+	}, http.StatusBadRequest)
+
+	// the service isn't ready while shutting down, before the HTTP server stops
+	cancel()
+	assertNotReady(t, baseURL)
+}
+
+func componentTestConfig(t *testing.T) config.Config {
+	t.Helper()
+
+	cfg := config.Default()
+	// the server listens on a random port, so tests can run in parallel
+	cfg.HTTP.Addr = "127.0.0.1:0"
+	cfg.Health.ShutdownDelay = time.Millisecond * 500
+
+	switch mode := os.Getenv("COMPONENT_TEST_MODE"); mode {
+	case "", "memory":
+		cfg.Broker.Backend = message.BrokerGoChannel
+		cfg.Storage.Backend = config.StorageMemory
+		cfg.Messaging.SchedulerPollInterval = time.Millisecond * 100
+	case "infra":
+		cfg.GatewayAddr = os.Getenv("GATEWAY_ADDR")
+		cfg.Redis.Addr = os.Getenv("REDIS_ADDR")
+		cfg.Postgres.URL = config.Secret(os.Getenv("POSTGRES_URL"))
+	default:
+		t.Fatalf("unknown COMPONENT_TEST_MODE %q, supported modes: memory, infra", mode)
+	}
+
+	return cfg
 }
 
-func waitForHttpServer(t *testing.T) {
+// waitForHttpServer returns the base URL of the service, once it's healthy.
+func waitForHttpServer(t *testing.T, svc service.Service) string {
 	t.Helper()
 
+	var baseURL string
+
 	require.EventuallyWithT(
 		t,
 		func(t *assert.CollectT) {
-			resp, err := http.Get("http://localhost:8080/health")
+			addr := svc.HTTPAddr()
+			if !assert.NotEmpty(t, addr, "HTTP server not listening yet") {
+				return
+			}
+			baseURL = "http://" + addr
+
+			resp, err := http.Get(baseURL + "/health/ready")
 			if !assert.NoError(t, err) {
 				return
 			}
@@ -110,6 +231,25 @@ func waitForHttpServer(t *testing.T) {
 		time.Second*10,
 		time.Millisecond*50,
 	)
+
+	return baseURL
+}
+
+func assertNotReady(t *testing.T, baseURL string) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			resp, err := http.Get(baseURL + "/health/ready")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		},
+		time.Millisecond*500,
+		time.Millisecond*20,
+	)
 }
 
 type TicketsStatusRequest struct {
@@ -124,12 +264,27 @@ type TicketStatus struct {
 	BookingID string `json:"booking_id"`
 }
 
+type CreateShowRequest struct {
+	DeadNationID    string    `json:"dead_nation_id"`
+	NumberOfTickets int       `json:"number_of_tickets"`
+	StartTime       time.Time `json:"start_time"`
+	Title           string    `json:"title"`
+	Venue           string    `json:"venue"`
+	TicketPrice     Money     `json:"ticket_price"`
+}
+
+type BookTicketsRequest struct {
+	ShowID          string `json:"show_id"`
+	NumberOfTickets int    `json:"number_of_tickets"`
+	CustomerEmail   string `json:"customer_email"`
+}
+
 type Money struct {
 	Amount   string `json:"amount"`
 	Currency string `json:"currency"`
 }
 
-func sendTicketsStatus(t *testing.T, req TicketsStatusRequest, idempotencyKey string) {
+func sendTicketsStatus(t *testing.T, baseURL string, req TicketsStatusRequest, idempotencyKey string) {
 	t.Helper()
 
 	payload, err := json.Marshal(req)
@@ -144,7 +299,7 @@ func sendTicketsStatus(t *testing.T, req TicketsStatusRequest, idempotencyKey st
 
 	httpReq, err := http.NewRequest(
 		http.MethodPost,
-		"http://localhost:8080/tickets-status",
+		baseURL+"/tickets-status",
 		bytes.NewBuffer(payload),
 	)
 	require.NoError(t, err)
@@ -158,36 +313,177 @@ func sendTicketsStatus(t *testing.T, req TicketsStatusRequest, idempotencyKey st
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func createShow(t *testing.T, baseURL string, req CreateShowRequest, idempotencyKey string) string {
+	t.Helper()
+
+	payload, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	httpReq, err := http.NewRequest(http.MethodPost, baseURL+"/shows", bytes.NewBuffer(payload))
+	require.NoError(t, err)
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var body struct {
+		ShowID string `json:"show_id"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	return body.ShowID
+}
+
+func bookTickets(t *testing.T, baseURL string, req BookTicketsRequest, expectedStatusCode int) string {
+	t.Helper()
+
+	payload, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	resp, err := http.Post(baseURL+"/book-tickets", "application/json", bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+
+	var body struct {
+		BookingID string `json:"booking_id"`
+	}
+	if resp.StatusCode == http.StatusCreated {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	}
+
+	return body.BookingID
+}
+
+func refundTicket(t *testing.T, baseURL string, ticketID string, expectedStatusCode int) {
+	t.Helper()
+
+	httpReq, err := http.NewRequest(
+		http.MethodPut,
+		baseURL+"/ticket-refund/"+ticketID,
+		nil,
+	)
+	require.NoError(t, err)
+
+	httpReq.Header.Set("Idempotency-Key", uuid.NewString())
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+}
+
+func assertPaymentRefunded(t *testing.T, paymentsService *api.PaymentsMock, ticketID string) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			var refund entities.PaymentRefund
+			var ok bool
+			for _, r := range paymentsService.PaymentRefunds() {
+				if r.TicketID == ticketID {
+					refund = r
+					ok = true
+					break
+				}
+			}
+
+			if !assert.Truef(t, ok, "payment for ticket %s not refunded", ticketID) {
+				return
+			}
+
+			assert.NotEmpty(t, refund.IdempotencyKey)
+		},
+		10*time.Second,
+		100*time.Millisecond,
+	)
+}
+
+func assertReceiptVoided(t *testing.T, receiptsService *api.ReceiptsServiceMock, ticketID string) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			var voided entities.VoidReceipt
+			var ok bool
+			for _, v := range receiptsService.Voided() {
+				if v.TicketID == ticketID {
+					voided = v
+					ok = true
+					break
+				}
+			}
+
+			if !assert.Truef(t, ok, "receipt for ticket %s not voided", ticketID) {
+				return
+			}
+
+			assert.NotEmpty(t, voided.IdempotencyKey)
+		},
+		10*time.Second,
+		100*time.Millisecond,
+	)
+}
+
 func assertReceiptForTicketIssued(t *testing.T, receiptsService *api.ReceiptsServiceMock, ticket TicketStatus) {
+	var receipt entities.IssueReceiptRequest
+
 	assert.EventuallyWithT(
 		t,
 		func(collectT *assert.CollectT) {
-			issuedReceipts := len(receiptsService.IssuedReceipts)
-			t.Log("issued receipts", issuedReceipts)
+			issuedReceipts := receiptsService.Receipts()
+			t.Log("issued receipts", len(issuedReceipts))
+
+			var ok bool
+			for _, issuedReceipt := range issuedReceipts {
+				if issuedReceipt.TicketID != ticket.TicketID {
+					continue
+				}
+				receipt = issuedReceipt
+				ok = true
+				break
+			}
 
-			assert.Greater(collectT, issuedReceipts, 0, "no receipts issued")
+			assert.Truef(collectT, ok, "receipt for ticket %s not found", ticket.TicketID)
 		},
 		10*time.Second,
 		100*time.Millisecond,
 	)
 
-	var receipt entities.IssueReceiptRequest
-	var ok bool
-	for _, issuedReceipt := range receiptsService.IssuedReceipts {
-		if issuedReceipt.TicketID != ticket.TicketID {
-			continue
-		}
-		receipt = issuedReceipt
-		ok = true
-		break
-	}
-	require.Truef(t, ok, "receipt for ticket %s not found", ticket.TicketID)
-
 	assert.Equal(t, ticket.TicketID, receipt.TicketID)
-	assert.Equal(t, ticket.Price.Amount, receipt.Price.Amount)
+	assert.Equal(t, ticket.Price.Amount, receipt.Price.Amount.String())
 	assert.Equal(t, ticket.Price.Currency, receipt.Price.Currency)
 }
 
+func assertPlacesBookedInDeadNation(t *testing.T, deadNationAPI *api.DeadNationMock, bookingID string, deadNationID string, numberOfTickets int) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			var booking entities.DeadNationBooking
+			var ok bool
+			for _, b := range deadNationAPI.Bookings() {
+				if b.BookingID.String() == bookingID {
+					booking = b
+					ok = true
+					break
+				}
+			}
+
+			if !assert.Truef(t, ok, "booking %s not sent to Dead Nation", bookingID) {
+				return
+			}
+
+			assert.Equal(t, deadNationID, booking.DeadNationEventID.String())
+			assert.Equal(t, numberOfTickets, booking.NumberOfTickets)
+			assert.NotEmpty(t, booking.IdempotencyKey)
+		},
+		10*time.Second,
+		100*time.Millisecond,
+	)
+}
+
 func assertRowToSheetAdded(t *testing.T, spreadsheetsService *api.SpreadsheetsAPIMock, ticket TicketStatus, sheetName string) bool {
 	return assert.EventuallyWithT(
 		t,
@@ -212,30 +508,106 @@ func assertRowToSheetAdded(t *testing.T, spreadsheetsService *api.SpreadsheetsAP
 	)
 }
 
-func assertTicketStoredInRepository(t *testing.T, postgres *pgxpool.Pool, ticket TicketStatus) {
-	ticketsRepo := db.NewTicketRepository(postgres)
+func assertTicketStoredInRepository(t *testing.T, baseURL string, ticket TicketStatus) {
+	assertTicketHasStatus(t, baseURL, ticket.TicketID, entities.TicketStatusConfirmed)
+}
 
-	assert.Eventually(
+// assertTicketHasStatus checks the tickets listed by the API, so it works with every storage.
+func assertTicketHasStatus(t *testing.T, baseURL string, ticketID string, status entities.TicketStatus) {
+	assert.EventuallyWithT(
 		t,
-		func() bool {
-			tickets, err := ticketsRepo.All(context.Background())
-			if err != nil {
-				return false
+		func(t *assert.CollectT) {
+			resp, err := http.Get(baseURL + "/tickets?status=" + string(status))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
 			}
 
-			for _, t := range tickets {
-				if t.ID == ticket.TicketID {
-					return true
-				}
+			var tickets []entities.Ticket
+			if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&tickets)) {
+				return
 			}
 
-			return false
+			ticketIDs := make([]string, 0, len(tickets))
+			for _, ticket := range tickets {
+				ticketIDs = append(ticketIDs, ticket.ID)
+			}
+
+			assert.Contains(t, ticketIDs, ticketID, "ticket %s not found with status %s", ticketID, status)
+		},
+		10*time.Second,
+		100*time.Millisecond,
+	)
+}
+
+func assertTicketFileServed(t *testing.T, baseURL string, ticket TicketStatus) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			resp, err := http.Get(baseURL + "/tickets/" + ticket.TicketID + "/file")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Contains(t, string(body), ticket.TicketID)
 		},
 		10*time.Second,
 		100*time.Millisecond,
 	)
 }
 
+func assertReceiptServed(t *testing.T, baseURL string, ticket TicketStatus) bool {
+	return assert.EventuallyWithT(
+		t,
+		func(t *assert.CollectT) {
+			resp, err := http.Get(baseURL + "/tickets/" + ticket.TicketID + "/receipt")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+
+			var receipt struct {
+				TicketID string `json:"ticket_id"`
+				Number   string `json:"number"`
+			}
+			if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&receipt)) {
+				return
+			}
+
+			assert.Equal(t, ticket.TicketID, receipt.TicketID)
+			assert.Equal(t, "mocked-receipt-number", receipt.Number)
+		},
+		10*time.Second,
+		100*time.Millisecond,
+	)
+}
+
+func assertTicketFileNotFound(t *testing.T, baseURL string, ticketID string) {
+	resp, err := http.Get(baseURL + "/tickets/" + ticketID + "/file")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func assertTicketUploaded(t *testing.T, service *api.FilesAPIClientMock, ticket TicketStatus) bool {
 	return assert.EventuallyWithT(
 		t,